@@ -0,0 +1,52 @@
+package compose
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff computes exponentially growing delays for retriable protocol operations
+// (e.g. resending messages or requesting proofs). It's not safe for concurrent use.
+type Backoff struct {
+	initial  time.Duration
+	maxDelay time.Duration
+	jitter   float64
+	rng      *rand.Rand
+
+	next time.Duration
+}
+
+// NewBackoff returns a backoff starting at initial and doubling on each Next call, capped at maxDelay
+// (no cap if maxDelay is 0). Each delay is reduced by a random fraction of up to jitter (in [0, 1]),
+// drawn from a generator seeded with seed, so that the same seed yields the same sequence.
+func NewBackoff(initial, maxDelay time.Duration, jitter float64, seed uint64) *Backoff {
+	return &Backoff{
+		initial:  initial,
+		maxDelay: maxDelay,
+		jitter:   min(1, max(0, jitter)),
+		rng:      rand.New(rand.NewPCG(seed, seed)),
+		next:     initial,
+	}
+}
+
+// Next returns the delay to wait before the next retry.
+func (b *Backoff) Next() time.Duration {
+	delay := b.next
+	if b.next <= math.MaxInt64/2 {
+		b.next *= 2
+	}
+	if b.maxDelay > 0 {
+		delay = min(delay, b.maxDelay)
+		b.next = min(b.next, b.maxDelay)
+	}
+	if b.jitter > 0 {
+		delay -= time.Duration(b.jitter * b.rng.Float64() * float64(delay))
+	}
+	return delay
+}
+
+// Reset restarts the sequence from the initial delay (e.g. after a successful operation).
+func (b *Backoff) Reset() {
+	b.next = b.initial
+}
@@ -0,0 +1,56 @@
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_DoublesUpToMax(t *testing.T) {
+	b := NewBackoff(10*time.Millisecond, 50*time.Millisecond, 0, 1)
+
+	var delays []time.Duration
+	for range 5 {
+		delays = append(delays, b.Next())
+	}
+	assert.Equal(t, []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		50 * time.Millisecond,
+		50 * time.Millisecond,
+	}, delays)
+
+	b.Reset()
+	assert.Equal(t, 10*time.Millisecond, b.Next())
+}
+
+func TestBackoff_JitterIsBoundedAndDeterministic(t *testing.T) {
+	sequence := func(seed uint64) []time.Duration {
+		b := NewBackoff(100*time.Millisecond, time.Second, 0.5, seed)
+		delays := make([]time.Duration, 0, 10)
+		for range 10 {
+			delays = append(delays, b.Next())
+		}
+		return delays
+	}
+
+	delays := sequence(42)
+	assert.Equal(t, delays, sequence(42))
+	assert.NotEqual(t, delays, sequence(43))
+
+	expected := 100 * time.Millisecond
+	for _, delay := range delays {
+		assert.LessOrEqual(t, delay, expected)
+		assert.GreaterOrEqual(t, delay, expected/2)
+		expected = min(2*expected, time.Second)
+	}
+}
+
+func TestBackoff_Unbounded(t *testing.T) {
+	b := NewBackoff(time.Hour, 0, 0, 1)
+	for range 100 {
+		assert.Positive(t, b.Next())
+	}
+}
@@ -0,0 +1,52 @@
+package compose
+
+import "errors"
+
+var ErrChainNotPermitted = errors.New("chain not permitted by the chain filter")
+
+// ChainFilter is a chain membership policy shared by the protocols, to reject requests touching chains
+// outside the configured roster. A chain is permitted if it's not denied and, when the allowlist is not empty,
+// it's allowed: Deny takes precedence over Allow. The zero value permits every chain.
+type ChainFilter struct {
+	// Permitted chains. If empty, every chain not denied is permitted.
+	Allow map[ChainID]struct{}
+	// Rejected chains, even if allowed.
+	Deny map[ChainID]struct{}
+}
+
+// NewChainFilter creates a filter from the allowed and denied chains.
+func NewChainFilter(allow, deny []ChainID) ChainFilter {
+	toSet := func(chains []ChainID) map[ChainID]struct{} {
+		if len(chains) == 0 {
+			return nil
+		}
+		set := make(map[ChainID]struct{}, len(chains))
+		for _, chainID := range chains {
+			set[chainID] = struct{}{}
+		}
+		return set
+	}
+	return ChainFilter{Allow: toSet(allow), Deny: toSet(deny)}
+}
+
+// IsPermitted returns whether the chain is permitted by the filter.
+func (f ChainFilter) IsPermitted(chainID ChainID) bool {
+	if _, denied := f.Deny[chainID]; denied {
+		return false
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	_, allowed := f.Allow[chainID]
+	return allowed
+}
+
+// PermitsRequest returns whether every chain of the request is permitted by the filter.
+func (f ChainFilter) PermitsRequest(request XTRequest) bool {
+	for _, txReq := range request.Transactions {
+		if !f.IsPermitted(txReq.ChainID) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainFilter_IsPermitted(t *testing.T) {
+	t.Run("zero_value", func(t *testing.T) {
+		var filter ChainFilter
+		assert.True(t, filter.IsPermitted(1))
+	})
+
+	t.Run("allow_only", func(t *testing.T) {
+		filter := NewChainFilter([]ChainID{1, 2}, nil)
+		assert.True(t, filter.IsPermitted(1))
+		assert.True(t, filter.IsPermitted(2))
+		assert.False(t, filter.IsPermitted(3))
+	})
+
+	t.Run("deny_only", func(t *testing.T) {
+		filter := NewChainFilter(nil, []ChainID{3})
+		assert.True(t, filter.IsPermitted(1))
+		assert.False(t, filter.IsPermitted(3))
+	})
+
+	t.Run("deny_takes_precedence", func(t *testing.T) {
+		filter := NewChainFilter([]ChainID{1, 2}, []ChainID{2, 3})
+		assert.True(t, filter.IsPermitted(1))
+		assert.False(t, filter.IsPermitted(2), "allowed but denied")
+		assert.False(t, filter.IsPermitted(3))
+		assert.False(t, filter.IsPermitted(4), "not allowed")
+	})
+}
+
+func TestChainFilter_PermitsRequest(t *testing.T) {
+	filter := NewChainFilter([]ChainID{1, 2}, nil)
+	request := XTRequest{Transactions: []TransactionRequest{
+		{ChainID: 1, Transactions: [][]byte{{1}}},
+		{ChainID: 2, Transactions: [][]byte{{2}}},
+	}}
+	assert.True(t, filter.PermitsRequest(request))
+
+	request.Transactions = append(request.Transactions, TransactionRequest{ChainID: 3, Transactions: [][]byte{{3}}})
+	assert.False(t, filter.PermitsRequest(request))
+}
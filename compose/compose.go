@@ -2,6 +2,9 @@ package compose
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 )
 
@@ -36,6 +39,22 @@ func (a EthAddress) String() string {
 	return "0x" + hex.EncodeToString(a[:])
 }
 
+// Sub returns p-n, or false if it would underflow.
+func (p PeriodID) Sub(n uint64) (PeriodID, bool) {
+	if uint64(p) < n {
+		return 0, false
+	}
+	return p - PeriodID(n), true
+}
+
+// Sub returns s-n, or false if it would underflow.
+func (s SuperblockNumber) Sub(n uint64) (SuperblockNumber, bool) {
+	if uint64(s) < n {
+		return 0, false
+	}
+	return s - SuperblockNumber(n), true
+}
+
 type TransactionRequest struct {
 	ChainID      ChainID
 	Transactions [][]byte
@@ -56,6 +75,13 @@ func (i *Instance) Chains() []ChainID {
 	return ChainsFromRequest(i.XTRequest)
 }
 
+// ExpectedParticipants returns the number of distinct chains that take part in the instance,
+// i.e. the number of votes the publisher waits for.
+// Publishers waiting on more chains may use it to scale their timeouts.
+func (i Instance) ExpectedParticipants() int {
+	return len(i.Chains())
+}
+
 func ChainsFromRequest(xtRequest XTRequest) []ChainID {
 	chainsMap := make(map[ChainID]bool)
 	for _, r := range xtRequest.Transactions {
@@ -68,6 +94,28 @@ func ChainsFromRequest(xtRequest XTRequest) []ChainID {
 	return chains
 }
 
+// ChainSetDiff returns the chains participating in b but not in a (added) and those participating in a
+// but not in b (removed), both sorted, e.g. to update per-chain bookkeeping when a request is re-proposed.
+func ChainSetDiff(a, b XTRequest) (added, removed []ChainID) {
+	before := ChainsFromRequest(a)
+	after := ChainsFromRequest(b)
+	added = make([]ChainID, 0)
+	for _, chainID := range after {
+		if !slices.Contains(before, chainID) {
+			added = append(added, chainID)
+		}
+	}
+	removed = make([]ChainID, 0)
+	for _, chainID := range before {
+		if !slices.Contains(after, chainID) {
+			removed = append(removed, chainID)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
 type DecisionState int
 
 const (
@@ -76,8 +124,10 @@ const (
 	DecisionStateRejected
 )
 
-func (d *DecisionState) String() string {
-	switch *d {
+var ErrUnknownDecisionState = errors.New("unknown decision state")
+
+func (d DecisionState) String() string {
+	switch d {
 	case DecisionStatePending:
 		return "Pending"
 	case DecisionStateAccepted:
@@ -88,3 +138,28 @@ func (d *DecisionState) String() string {
 		return "Unknown"
 	}
 }
+
+// MarshalText encodes the decision state as "Pending", "Accepted" or "Rejected".
+func (d DecisionState) MarshalText() ([]byte, error) {
+	switch d {
+	case DecisionStatePending, DecisionStateAccepted, DecisionStateRejected:
+		return []byte(d.String()), nil
+	default:
+		return nil, fmt.Errorf("%d: %w", int(d), ErrUnknownDecisionState)
+	}
+}
+
+// UnmarshalText decodes a decision state encoded by MarshalText.
+func (d *DecisionState) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "Pending":
+		*d = DecisionStatePending
+	case "Accepted":
+		*d = DecisionStateAccepted
+	case "Rejected":
+		*d = DecisionStateRejected
+	default:
+		return fmt.Errorf("%q: %w", text, ErrUnknownDecisionState)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecisionState_StringOnValue(t *testing.T) {
+	state := DecisionStateAccepted
+	assert.Equal(t, "Accepted", state.String())
+	assert.Equal(t, "Rejected", fmt.Sprint(DecisionStateRejected))
+	assert.Equal(t, "Pending", fmt.Sprintf("%v", DecisionStatePending))
+	assert.Equal(t, "Unknown", DecisionState(42).String())
+}
+
+func TestDecisionState_TextRoundTrip(t *testing.T) {
+	for _, state := range []DecisionState{DecisionStatePending, DecisionStateAccepted, DecisionStateRejected} {
+		text, err := state.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, state.String(), string(text))
+
+		var decoded DecisionState
+		require.NoError(t, decoded.UnmarshalText(text))
+		assert.Equal(t, state, decoded)
+	}
+
+	// JSON uses the text encoding
+	encoded, err := json.Marshal(map[string]DecisionState{"state": DecisionStateAccepted})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"state":"Accepted"}`, string(encoded))
+	var decoded map[string]DecisionState
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, DecisionStateAccepted, decoded["state"])
+}
+
+func TestDecisionState_TextUnknown(t *testing.T) {
+	_, err := DecisionState(42).MarshalText()
+	require.ErrorIs(t, err, ErrUnknownDecisionState)
+
+	var decoded DecisionState
+	require.ErrorIs(t, decoded.UnmarshalText([]byte("Unknown")), ErrUnknownDecisionState)
+	require.ErrorIs(t, decoded.UnmarshalText([]byte("accepted")), ErrUnknownDecisionState)
+}
+
+func TestInstance_ExpectedParticipants(t *testing.T) {
+	instance := Instance{
+		XTRequest: XTRequest{
+			Transactions: []TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+				{ChainID: 1, Transactions: [][]byte{[]byte("c")}},
+				{ChainID: 3, Transactions: [][]byte{[]byte("d")}},
+			},
+		},
+	}
+	assert.Equal(t, 3, instance.ExpectedParticipants())
+	assert.Len(t, instance.Chains(), instance.ExpectedParticipants())
+
+	assert.Equal(t, 0, Instance{}.ExpectedParticipants())
+}
+
+func TestPeriodID_Sub(t *testing.T) {
+	period, ok := PeriodID(5).Sub(2)
+	assert.True(t, ok)
+	assert.Equal(t, PeriodID(3), period)
+
+	period, ok = PeriodID(1).Sub(1)
+	assert.True(t, ok)
+	assert.Equal(t, PeriodID(0), period)
+
+	_, ok = PeriodID(0).Sub(1)
+	assert.False(t, ok)
+}
+
+func TestSuperblockNumber_Sub(t *testing.T) {
+	superblock, ok := SuperblockNumber(5).Sub(5)
+	assert.True(t, ok)
+	assert.Equal(t, SuperblockNumber(0), superblock)
+
+	_, ok = SuperblockNumber(3).Sub(4)
+	assert.False(t, ok)
+}
+
+func TestChainSetDiff(t *testing.T) {
+	request := func(chainIDs ...ChainID) XTRequest {
+		req := XTRequest{}
+		for _, chainID := range chainIDs {
+			req.Transactions = append(req.Transactions, TransactionRequest{
+				ChainID:      chainID,
+				Transactions: [][]byte{{1}},
+			})
+		}
+		return req
+	}
+
+	t.Run("overlapping", func(t *testing.T) {
+		added, removed := ChainSetDiff(request(3, 1, 2), request(4, 2, 5, 1))
+		assert.Equal(t, []ChainID{4, 5}, added)
+		assert.Equal(t, []ChainID{3}, removed)
+	})
+
+	t.Run("disjoint", func(t *testing.T) {
+		added, removed := ChainSetDiff(request(2, 1), request(4, 3))
+		assert.Equal(t, []ChainID{3, 4}, added)
+		assert.Equal(t, []ChainID{1, 2}, removed)
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		// Repeated chains and order don't matter
+		added, removed := ChainSetDiff(request(1, 2, 1), request(2, 1))
+		assert.Empty(t, added)
+		assert.Empty(t, removed)
+	})
+}
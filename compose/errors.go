@@ -0,0 +1,31 @@
+package compose
+
+import "errors"
+
+// Shared error categories. Protocol packages define their own sentinel errors as Errors of these categories,
+// so that callers can match a category with errors.Is regardless of which protocol raised it.
+var (
+	ErrNoTransactions = errors.New("no transactions")
+	ErrDuplicatedVote = errors.New("duplicated vote")
+	ErrNotParticipant = errors.New("not a participant")
+	ErrPeriodMismatch = errors.New("period mismatch")
+)
+
+// Error is a protocol error belonging to a shared category.
+type Error struct {
+	Category error
+	Msg      string
+}
+
+// NewError creates a protocol error with the given message, matching the category with errors.Is.
+func NewError(category error, msg string) error {
+	return &Error{Category: category, Msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Category
+}
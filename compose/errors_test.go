@@ -0,0 +1,27 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_MatchesCategory(t *testing.T) {
+	err := NewError(ErrNoTransactions, "no transactions to execute")
+	assert.Equal(t, "no transactions to execute", err.Error())
+	assert.ErrorIs(t, err, ErrNoTransactions)
+	assert.NotErrorIs(t, err, ErrDuplicatedVote)
+
+	// Matches through further wrapping, and the sentinel itself stays distinguishable
+	wrapped := fmt.Errorf("simulating: %w", err)
+	assert.ErrorIs(t, wrapped, ErrNoTransactions)
+	assert.ErrorIs(t, wrapped, err)
+	assert.NotErrorIs(t, NewError(ErrNoTransactions, "other"), err)
+
+	var protocolErr *Error
+	if assert.True(t, errors.As(wrapped, &protocolErr)) {
+		assert.Equal(t, ErrNoTransactions, protocolErr.Category)
+	}
+}
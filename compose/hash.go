@@ -0,0 +1,96 @@
+package compose
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidHashLength = errors.New("invalid hash length")
+
+func (h TxHash) String() string         { return hex.EncodeToString(h[:]) }
+func (h SuperblockHash) String() string { return hex.EncodeToString(h[:]) }
+func (h BlockHash) String() string      { return hex.EncodeToString(h[:]) }
+func (h StateRoot) String() string      { return hex.EncodeToString(h[:]) }
+
+func (h TxHash) IsZero() bool         { return h == TxHash{} }
+func (h SuperblockHash) IsZero() bool { return h == SuperblockHash{} }
+func (h BlockHash) IsZero() bool      { return h == BlockHash{} }
+func (h StateRoot) IsZero() bool      { return h == StateRoot{} }
+func (id InstanceID) IsZero() bool    { return id == InstanceID{} }
+
+// Hashes are encoded as hex strings in text formats (e.g. JSON), and parsed with or without 0x prefix.
+func (h TxHash) MarshalText() ([]byte, error)         { return []byte(h.String()), nil }
+func (h SuperblockHash) MarshalText() ([]byte, error) { return []byte(h.String()), nil }
+func (h BlockHash) MarshalText() ([]byte, error)      { return []byte(h.String()), nil }
+func (h StateRoot) MarshalText() ([]byte, error)      { return []byte(h.String()), nil }
+func (id InstanceID) MarshalText() ([]byte, error)    { return []byte(id.String()), nil }
+
+func (h *TxHash) UnmarshalText(text []byte) (err error) {
+	*h, err = ParseTxHash(string(text))
+	return err
+}
+
+func (h *SuperblockHash) UnmarshalText(text []byte) (err error) {
+	*h, err = ParseSuperblockHash(string(text))
+	return err
+}
+
+func (h *BlockHash) UnmarshalText(text []byte) (err error) {
+	*h, err = ParseBlockHash(string(text))
+	return err
+}
+
+func (h *StateRoot) UnmarshalText(text []byte) (err error) {
+	*h, err = ParseStateRoot(string(text))
+	return err
+}
+
+func (id *InstanceID) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseInstanceID(string(text))
+	return err
+}
+
+// ParseTxHash parses a hex string (with or without 0x prefix) into a TxHash.
+func ParseTxHash(s string) (TxHash, error) {
+	b, err := parseHash32(s)
+	return TxHash(b), err
+}
+
+// ParseSuperblockHash parses a hex string (with or without 0x prefix) into a SuperblockHash.
+func ParseSuperblockHash(s string) (SuperblockHash, error) {
+	b, err := parseHash32(s)
+	return SuperblockHash(b), err
+}
+
+// ParseBlockHash parses a hex string (with or without 0x prefix) into a BlockHash.
+func ParseBlockHash(s string) (BlockHash, error) {
+	b, err := parseHash32(s)
+	return BlockHash(b), err
+}
+
+// ParseStateRoot parses a hex string (with or without 0x prefix) into a StateRoot.
+func ParseStateRoot(s string) (StateRoot, error) {
+	b, err := parseHash32(s)
+	return StateRoot(b), err
+}
+
+// ParseInstanceID parses a hex string (with or without 0x prefix) into an InstanceID.
+func ParseInstanceID(s string) (InstanceID, error) {
+	b, err := parseHash32(s)
+	return InstanceID(b), err
+}
+
+func parseHash32(s string) ([32]byte, error) {
+	var out [32]byte
+	decoded, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return out, err
+	}
+	if len(decoded) != len(out) {
+		return out, fmt.Errorf("expected %d bytes, got %d: %w", len(out), len(decoded), ErrInvalidHashLength)
+	}
+	copy(out[:], decoded)
+	return out, nil
+}
@@ -0,0 +1,103 @@
+package compose
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashes_StringParseRoundTrip(t *testing.T) {
+	raw := [32]byte{0xAB, 0x01}
+	raw[31] = 0xFF
+	expected := "ab01" + strings.Repeat("00", 29) + "ff"
+
+	txHash := TxHash(raw)
+	assert.Equal(t, expected, txHash.String())
+	parsedTx, err := ParseTxHash(txHash.String())
+	require.NoError(t, err)
+	assert.Equal(t, txHash, parsedTx)
+
+	superblockHash := SuperblockHash(raw)
+	assert.Equal(t, expected, superblockHash.String())
+	parsedSuperblock, err := ParseSuperblockHash(superblockHash.String())
+	require.NoError(t, err)
+	assert.Equal(t, superblockHash, parsedSuperblock)
+
+	blockHash := BlockHash(raw)
+	assert.Equal(t, expected, blockHash.String())
+	parsedBlock, err := ParseBlockHash(blockHash.String())
+	require.NoError(t, err)
+	assert.Equal(t, blockHash, parsedBlock)
+
+	stateRoot := StateRoot(raw)
+	assert.Equal(t, expected, stateRoot.String())
+	parsedRoot, err := ParseStateRoot(stateRoot.String())
+	require.NoError(t, err)
+	assert.Equal(t, stateRoot, parsedRoot)
+
+	instanceID := InstanceID(raw)
+	parsedID, err := ParseInstanceID(instanceID.String())
+	require.NoError(t, err)
+	assert.Equal(t, instanceID, parsedID)
+
+	// 0x prefix and upper case are accepted
+	parsedRoot, err = ParseStateRoot("0x" + strings.ToUpper(expected))
+	require.NoError(t, err)
+	assert.Equal(t, stateRoot, parsedRoot)
+}
+
+func TestHashes_ParseErrors(t *testing.T) {
+	_, err := ParseBlockHash("abcd")
+	require.ErrorIs(t, err, ErrInvalidHashLength)
+
+	_, err = ParseBlockHash(strings.Repeat("00", 33))
+	require.ErrorIs(t, err, ErrInvalidHashLength)
+
+	_, err = ParseBlockHash(strings.Repeat("zz", 32))
+	require.Error(t, err)
+}
+
+func TestHashes_IsZero(t *testing.T) {
+	assert.True(t, TxHash{}.IsZero())
+	assert.True(t, SuperblockHash{}.IsZero())
+	assert.True(t, BlockHash{}.IsZero())
+	assert.True(t, StateRoot{}.IsZero())
+	assert.True(t, InstanceID{}.IsZero())
+
+	assert.False(t, TxHash{1}.IsZero())
+	assert.False(t, SuperblockHash{1}.IsZero())
+	assert.False(t, BlockHash{31: 1}.IsZero())
+	assert.False(t, StateRoot{1}.IsZero())
+	assert.False(t, InstanceID{1}.IsZero())
+}
+
+func TestHashes_JSONRoundTrip(t *testing.T) {
+	type hashes struct {
+		Tx         TxHash
+		Superblock SuperblockHash
+		Block      BlockHash
+		StateRoot  StateRoot
+		Instance   InstanceID
+	}
+	in := hashes{
+		Tx:         TxHash{1},
+		Superblock: SuperblockHash{2},
+		Block:      BlockHash{3},
+		StateRoot:  StateRoot{4},
+		Instance:   InstanceID{5},
+	}
+
+	encoded, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"Superblock":"02`+strings.Repeat("00", 31)+`"`)
+
+	var out hashes
+	require.NoError(t, json.Unmarshal(encoded, &out))
+	assert.Equal(t, in, out)
+
+	var invalid SuperblockHash
+	require.ErrorIs(t, json.Unmarshal([]byte(`"0xabcd"`), &invalid), ErrInvalidHashLength)
+}
@@ -0,0 +1,29 @@
+package compose
+
+import "time"
+
+// PeriodStart returns the wall-clock time at which the given period starts,
+// with period 0 starting at genesis and each one lasting PeriodDuration.
+func PeriodStart(genesis time.Time, id PeriodID) time.Time {
+	return genesis.Add(time.Duration(id) * PeriodDuration)
+}
+
+// PeriodForTime returns the period running at t, i.e. the one whose window [start, start + PeriodDuration)
+// contains it. A time exactly on a boundary belongs to the period starting there, and times before genesis to period 0.
+func PeriodForTime(genesis, t time.Time) PeriodID {
+	if t.Before(genesis) {
+		return 0
+	}
+	return PeriodID(t.Sub(genesis) / PeriodDuration)
+}
+
+// PeriodDeadline returns the time by which the superblock of the given period must be proven, given the
+// publisher's proof window (e.g. ProofWindow): the start of the first period that can't begin before it's finalized,
+// proofWindow periods after the next one. Drivers call ProofTimeout for the superblock if it's still pending by then.
+// A 0 window means no constraint, so there's no deadline and false is returned.
+func PeriodDeadline(genesis time.Time, id PeriodID, proofWindow uint64) (time.Time, bool) {
+	if proofWindow == 0 {
+		return time.Time{}, false
+	}
+	return PeriodStart(genesis, id+PeriodID(proofWindow)+1), true
+}
@@ -0,0 +1,46 @@
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodStart(t *testing.T) {
+	genesis := time.Unix(1_700_000_000, 0)
+
+	assert.Equal(t, genesis, PeriodStart(genesis, 0))
+	assert.Equal(t, genesis.Add(3*PeriodDuration), PeriodStart(genesis, 3))
+}
+
+func TestPeriodForTime(t *testing.T) {
+	genesis := time.Unix(1_700_000_000, 0)
+
+	assert.Equal(t, PeriodID(0), PeriodForTime(genesis, genesis.Add(-time.Second)))
+	assert.Equal(t, PeriodID(0), PeriodForTime(genesis, genesis))
+	assert.Equal(t, PeriodID(0), PeriodForTime(genesis, genesis.Add(PeriodDuration-time.Nanosecond)))
+	// Boundaries belong to the period starting there
+	assert.Equal(t, PeriodID(1), PeriodForTime(genesis, genesis.Add(PeriodDuration)))
+	assert.Equal(t, PeriodID(5), PeriodForTime(genesis, PeriodStart(genesis, 5)))
+	assert.Equal(t, PeriodID(5), PeriodForTime(genesis, PeriodStart(genesis, 5).Add(PeriodDuration/2)))
+}
+
+func TestPeriodDeadline(t *testing.T) {
+	genesis := time.Unix(1_700_000_000, 0)
+
+	deadline, ok := PeriodDeadline(genesis, 4, ProofWindow)
+	require.True(t, ok)
+	assert.Equal(t, PeriodStart(genesis, 4+ProofWindow+1), deadline)
+	assert.Equal(t, PeriodID(4+ProofWindow+1), PeriodForTime(genesis, deadline))
+
+	// Configured windows are honored
+	deadline, ok = PeriodDeadline(genesis, 4, 2)
+	require.True(t, ok)
+	assert.Equal(t, PeriodStart(genesis, 7), deadline)
+
+	// No window, no deadline
+	_, ok = PeriodDeadline(genesis, 4, 0)
+	assert.False(t, ok)
+}
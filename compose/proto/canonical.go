@@ -0,0 +1,250 @@
+package proto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	goproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var ErrInvalidCanonicalJSON = errors.New("invalid canonical JSON")
+
+// MarshalJSONCanonical renders a protocol message as canonical JSON, meant for debugging and cross-language interop:
+// fields are keyed by their proto name in lexicographic order, only populated fields are emitted,
+// bytes are hex strings (without 0x prefix), 64-bit integers are decimal strings and enums are their value names.
+// Unlike protojson, the output is stable, so equal messages always render to the same bytes.
+func MarshalJSONCanonical(m goproto.Message) ([]byte, error) {
+	value, err := canonicalMessage(m.ProtoReflect())
+	if err != nil {
+		return nil, err
+	}
+
+	// encoding/json sorts map keys, which gives the stable field ordering
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// UnmarshalJSONCanonical parses canonical JSON produced by MarshalJSONCanonical into m, which is reset first.
+// Hex strings are accepted with or without 0x prefix. Unknown fields return ErrInvalidCanonicalJSON.
+func UnmarshalJSONCanonical(data []byte, m goproto.Message) error {
+	goproto.Reset(m)
+	return parseCanonicalMessage(json.RawMessage(data), m.ProtoReflect())
+}
+
+func canonicalMessage(m protoreflect.Message) (map[string]any, error) {
+	out := make(map[string]any)
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		var value any
+		switch {
+		case fd.IsList():
+			list := v.List()
+			values := make([]any, list.Len())
+			for i := range list.Len() {
+				if values[i], err = canonicalSingular(fd, list.Get(i)); err != nil {
+					return false
+				}
+			}
+			value = values
+		case fd.IsMap():
+			entries := make(map[string]any)
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				entries[k.String()], err = canonicalSingular(fd.MapValue(), mv)
+				return err == nil
+			})
+			value = entries
+		default:
+			value, err = canonicalSingular(fd, v)
+		}
+		if err != nil {
+			return false
+		}
+		out[string(fd.Name())] = value
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func canonicalSingular(fd protoreflect.FieldDescriptor, v protoreflect.Value) (any, error) {
+	switch fd.Kind() {
+	case protoreflect.BytesKind:
+		return hex.EncodeToString(v.Bytes()), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name()), nil
+		}
+		return int32(v.Enum()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return canonicalMessage(v.Message())
+	default:
+		// bool, string, 32-bit integers and floats are rendered as native JSON values
+		return v.Interface(), nil
+	}
+}
+
+func parseCanonicalMessage(data json.RawMessage, m protoreflect.Message) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("%s: %w: %w", m.Descriptor().FullName(), ErrInvalidCanonicalJSON, err)
+	}
+
+	descriptors := m.Descriptor().Fields()
+	for name, raw := range fields {
+		fd := descriptors.ByName(protoreflect.Name(name))
+		if fd == nil {
+			return fmt.Errorf("unknown field %s.%s: %w", m.Descriptor().FullName(), name, ErrInvalidCanonicalJSON)
+		}
+		if err := parseCanonicalField(raw, m, fd); err != nil {
+			return fmt.Errorf("field %s: %w", fd.FullName(), err)
+		}
+	}
+	return nil
+}
+
+func parseCanonicalField(raw json.RawMessage, m protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	switch {
+	case fd.IsList():
+		var elements []json.RawMessage
+		if err := json.Unmarshal(raw, &elements); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCanonicalJSON, err)
+		}
+		list := m.Mutable(fd).List()
+		for _, element := range elements {
+			var value protoreflect.Value
+			if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+				value = list.NewElement()
+				if err := parseCanonicalMessage(element, value.Message()); err != nil {
+					return err
+				}
+			} else {
+				var err error
+				if value, err = parseCanonicalScalar(element, fd); err != nil {
+					return err
+				}
+			}
+			list.Append(value)
+		}
+	case fd.IsMap():
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCanonicalJSON, err)
+		}
+		entriesMap := m.Mutable(fd).Map()
+		for k, element := range entries {
+			key, err := parseCanonicalScalar(json.RawMessage(strconv.Quote(k)), fd.MapKey())
+			if err != nil {
+				return err
+			}
+			var value protoreflect.Value
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				value = entriesMap.NewValue()
+				if err := parseCanonicalMessage(element, value.Message()); err != nil {
+					return err
+				}
+			} else if value, err = parseCanonicalScalar(element, fd.MapValue()); err != nil {
+				return err
+			}
+			entriesMap.Set(key.MapKey(), value)
+		}
+	case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+		return parseCanonicalMessage(raw, m.Mutable(fd).Message())
+	default:
+		value, err := parseCanonicalScalar(raw, fd)
+		if err != nil {
+			return err
+		}
+		m.Set(fd, value)
+	}
+	return nil
+}
+
+func parseCanonicalScalar(raw json.RawMessage, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	var (
+		value protoreflect.Value
+		err   error
+	)
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		var b bool
+		err = json.Unmarshal(raw, &b)
+		value = protoreflect.ValueOfBool(b)
+	case protoreflect.StringKind:
+		var s string
+		err = json.Unmarshal(raw, &s)
+		value = protoreflect.ValueOfString(s)
+	case protoreflect.BytesKind:
+		var s string
+		if err = json.Unmarshal(raw, &s); err == nil {
+			var b []byte
+			b, err = hex.DecodeString(strings.TrimPrefix(s, "0x"))
+			value = protoreflect.ValueOfBytes(b)
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		var i int32
+		err = json.Unmarshal(raw, &i)
+		value = protoreflect.ValueOfInt32(i)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		var u uint32
+		err = json.Unmarshal(raw, &u)
+		value = protoreflect.ValueOfUint32(u)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		var s string
+		if err = json.Unmarshal(raw, &s); err == nil {
+			var i int64
+			i, err = strconv.ParseInt(s, 10, 64)
+			value = protoreflect.ValueOfInt64(i)
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		var s string
+		if err = json.Unmarshal(raw, &s); err == nil {
+			var u uint64
+			u, err = strconv.ParseUint(s, 10, 64)
+			value = protoreflect.ValueOfUint64(u)
+		}
+	case protoreflect.FloatKind:
+		var f float32
+		err = json.Unmarshal(raw, &f)
+		value = protoreflect.ValueOfFloat32(f)
+	case protoreflect.DoubleKind:
+		var f float64
+		err = json.Unmarshal(raw, &f)
+		value = protoreflect.ValueOfFloat64(f)
+	case protoreflect.EnumKind:
+		var name string
+		if err = json.Unmarshal(raw, &name); err == nil {
+			ev := fd.Enum().Values().ByName(protoreflect.Name(name))
+			if ev == nil {
+				return value, fmt.Errorf("unknown enum value %q: %w", name, ErrInvalidCanonicalJSON)
+			}
+			value = protoreflect.ValueOfEnum(ev.Number())
+		} else {
+			var number int32
+			err = json.Unmarshal(raw, &number)
+			value = protoreflect.ValueOfEnum(protoreflect.EnumNumber(number))
+		}
+	default:
+		return value, fmt.Errorf("unsupported field kind %s: %w", fd.Kind(), ErrInvalidCanonicalJSON)
+	}
+	if err != nil {
+		return value, fmt.Errorf("%w: %w", ErrInvalidCanonicalJSON, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,83 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func TestJSONCanonical_StartInstance(t *testing.T) {
+	msg := &StartInstance{
+		InstanceId:     []byte{0xab, 0xcd},
+		PeriodId:       2,
+		SequenceNumber: 3,
+		XtRequest: &XTRequest{TransactionRequests: []*TransactionRequest{
+			{ChainId: 1, Transaction: [][]byte{{0x01, 0x02}, {0xff}}},
+			{ChainId: 2, Transaction: [][]byte{{0x10}}},
+		}},
+	}
+
+	data, err := MarshalJSONCanonical(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"instance_id": "abcd",
+		"period_id": "2",
+		"sequence_number": "3",
+		"xt_request": {"transaction_requests": [
+			{"chain_id": "1", "transaction": ["0102", "ff"]},
+			{"chain_id": "2", "transaction": ["10"]}
+		]}
+	}`, string(data))
+
+	// Stable field ordering
+	assert.Regexp(t, `^\{"instance_id":.*"period_id":.*"sequence_number":.*"xt_request":`, string(data))
+	again, err := MarshalJSONCanonical(goproto.Clone(msg))
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	decoded := &StartInstance{}
+	require.NoError(t, UnmarshalJSONCanonical(data, decoded))
+	assert.True(t, goproto.Equal(msg, decoded))
+}
+
+func TestJSONCanonical_RoundTripEnvelope(t *testing.T) {
+	msg := WrapMailboxMessage("sender", &MailboxMessage{
+		SessionId:        1,
+		InstanceId:       []byte{1},
+		SourceChain:      2,
+		DestinationChain: 3,
+		Source:           []byte{4},
+		Receiver:         []byte{5},
+		Label:            "<label>",
+		Data:             [][]byte{{6}},
+	})
+
+	data, err := MarshalJSONCanonical(msg)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"label":"<label>"`)
+
+	decoded := &Message{}
+	require.NoError(t, UnmarshalJSONCanonical(data, decoded))
+	assert.True(t, goproto.Equal(msg, decoded))
+}
+
+func TestUnmarshalJSONCanonical(t *testing.T) {
+	t.Run("hex_prefix", func(t *testing.T) {
+		decoded := &Decided{}
+		require.NoError(t, UnmarshalJSONCanonical([]byte(`{"instance_id":"0x0a0b","decision":true}`), decoded))
+		assert.Equal(t, []byte{0x0a, 0x0b}, decoded.InstanceId)
+		assert.True(t, decoded.Decision)
+	})
+
+	for name, data := range map[string]string{
+		"unknown_field": `{"instanceId":"0a"}`,
+		"invalid_hex":   `{"instance_id":"zz"}`,
+		"not_an_object": `[]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, UnmarshalJSONCanonical([]byte(data), &Decided{}), ErrInvalidCanonicalJSON)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package proto
+
+// NewMessage builds a Message envelope from the sender ID and payload.
+// A nil payload yields an envelope without payload.
+func NewMessage(senderID string, payload isMessage_Payload) *Message {
+	return &Message{
+		SenderId: senderID,
+		Payload:  payload,
+	}
+}
+
+// wrap builds a Message envelope setting the oneof through wrapper,
+// or an envelope without payload if the payload is nil.
+func wrap[T any](senderID string, payload *T, wrapper func(*T) isMessage_Payload) *Message {
+	if payload == nil {
+		return NewMessage(senderID, nil)
+	}
+	return NewMessage(senderID, wrapper(payload))
+}
+
+// WrapHandshakeRequest builds a Message envelope carrying a HandshakeRequest.
+func WrapHandshakeRequest(senderID string, m *HandshakeRequest) *Message {
+	return wrap(senderID, m, func(m *HandshakeRequest) isMessage_Payload {
+		return &Message_HandshakeRequest{HandshakeRequest: m}
+	})
+}
+
+// WrapHandshakeResponse builds a Message envelope carrying a HandshakeResponse.
+func WrapHandshakeResponse(senderID string, m *HandshakeResponse) *Message {
+	return wrap(senderID, m, func(m *HandshakeResponse) isMessage_Payload {
+		return &Message_HandshakeResponse{HandshakeResponse: m}
+	})
+}
+
+// WrapPing builds a Message envelope carrying a Ping.
+func WrapPing(senderID string, m *Ping) *Message {
+	return wrap(senderID, m, func(m *Ping) isMessage_Payload { return &Message_Ping{Ping: m} })
+}
+
+// WrapPong builds a Message envelope carrying a Pong.
+func WrapPong(senderID string, m *Pong) *Message {
+	return wrap(senderID, m, func(m *Pong) isMessage_Payload { return &Message_Pong{Pong: m} })
+}
+
+// WrapXTRequest builds a Message envelope carrying an XTRequest.
+func WrapXTRequest(senderID string, m *XTRequest) *Message {
+	return wrap(senderID, m, func(m *XTRequest) isMessage_Payload { return &Message_XtRequest{XtRequest: m} })
+}
+
+// WrapStartInstance builds a Message envelope carrying a StartInstance.
+func WrapStartInstance(senderID string, m *StartInstance) *Message {
+	return wrap(senderID, m, func(m *StartInstance) isMessage_Payload {
+		return &Message_StartInstance{StartInstance: m}
+	})
+}
+
+// WrapVote builds a Message envelope carrying a Vote.
+func WrapVote(senderID string, m *Vote) *Message {
+	return wrap(senderID, m, func(m *Vote) isMessage_Payload { return &Message_Vote{Vote: m} })
+}
+
+// WrapDecided builds a Message envelope carrying a Decided.
+func WrapDecided(senderID string, m *Decided) *Message {
+	return wrap(senderID, m, func(m *Decided) isMessage_Payload { return &Message_Decided{Decided: m} })
+}
+
+// WrapMailboxMessage builds a Message envelope carrying a MailboxMessage.
+func WrapMailboxMessage(senderID string, m *MailboxMessage) *Message {
+	return wrap(senderID, m, func(m *MailboxMessage) isMessage_Payload {
+		return &Message_MailboxMessage{MailboxMessage: m}
+	})
+}
+
+// WrapStartPeriod builds a Message envelope carrying a StartPeriod.
+func WrapStartPeriod(senderID string, m *StartPeriod) *Message {
+	return wrap(senderID, m, func(m *StartPeriod) isMessage_Payload { return &Message_StartPeriod{StartPeriod: m} })
+}
+
+// WrapRollback builds a Message envelope carrying a Rollback.
+func WrapRollback(senderID string, m *Rollback) *Message {
+	return wrap(senderID, m, func(m *Rollback) isMessage_Payload { return &Message_Rollback{Rollback: m} })
+}
+
+// WrapProof builds a Message envelope carrying a Proof.
+func WrapProof(senderID string, m *Proof) *Message {
+	return wrap(senderID, m, func(m *Proof) isMessage_Payload { return &Message_Proof{Proof: m} })
+}
+
+// WrapNativeDecided builds a Message envelope carrying a NativeDecided.
+func WrapNativeDecided(senderID string, m *NativeDecided) *Message {
+	return wrap(senderID, m, func(m *NativeDecided) isMessage_Payload {
+		return &Message_NativeDecided{NativeDecided: m}
+	})
+}
+
+// WrapWSDecided builds a Message envelope carrying a WSDecided.
+func WrapWSDecided(senderID string, m *WSDecided) *Message {
+	return wrap(senderID, m, func(m *WSDecided) isMessage_Payload { return &Message_WsDecided{WsDecided: m} })
+}
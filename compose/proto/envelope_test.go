@@ -0,0 +1,38 @@
+package proto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func TestWrapVote_StableEncoding(t *testing.T) {
+	m := WrapVote("sp", &Vote{InstanceId: []byte{1, 2}, ChainId: 7, Vote: true})
+	require.NotNil(t, m.GetVote())
+	assert.Equal(t, "sp", m.GetSenderId())
+
+	data, err := goproto.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, "0a02737042080a02010210071801", hex.EncodeToString(data))
+}
+
+func TestWrap_SetsOneof(t *testing.T) {
+	assert.NotNil(t, WrapStartInstance("sp", &StartInstance{PeriodId: 1}).GetStartInstance())
+	assert.NotNil(t, WrapMailboxMessage("a", &MailboxMessage{Label: "x"}).GetMailboxMessage())
+	assert.NotNil(t, WrapXTRequest("a", &XTRequest{}).GetXtRequest())
+	assert.NotNil(t, WrapWSDecided("a", &WSDecided{}).GetWsDecided())
+}
+
+func TestWrap_NilPayload(t *testing.T) {
+	m := WrapVote("sp", nil)
+	require.NotNil(t, m)
+	assert.Nil(t, m.GetPayload())
+	assert.Nil(t, NewMessage("sp", nil).GetPayload())
+
+	data, err := goproto.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, "0a027370", hex.EncodeToString(data))
+}
@@ -0,0 +1,73 @@
+package proto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxFrameSize is the maximum frame size accepted by ReadMessage.
+const DefaultMaxFrameSize = 4 << 20 // 4 MiB
+
+// frameHeaderSize is the size of the big-endian length prefix of each frame.
+const frameHeaderSize = 4
+
+var ErrFrameTooLarge = errors.New("frame too large")
+
+// WriteMessage writes the message to w as a single frame:
+// a 4-byte big-endian length prefix followed by the marshaled message.
+func WriteMessage(w io.Writer, m *Message) error {
+	data, err := goproto.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+	if uint64(len(data)) > math.MaxUint32 {
+		return fmt.Errorf("frame of %d bytes: %w", len(data), ErrFrameTooLarge)
+	}
+
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	frame = append(frame, data...)
+	if _, err := w.Write(frame); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+	return nil
+}
+
+// ReadMessage reads a single frame written by WriteMessage, rejecting frames bigger than DefaultMaxFrameSize.
+func ReadMessage(r io.Reader) (*Message, error) {
+	return ReadMessageWithLimit(r, DefaultMaxFrameSize)
+}
+
+// ReadMessageWithLimit reads a single frame written by WriteMessage, rejecting frames bigger than maxFrameSize
+// before allocating them, to guard against hostile senders.
+// It returns io.EOF if the stream ends cleanly before a frame, and io.ErrUnexpectedEOF if it ends mid-frame.
+func ReadMessageWithLimit(r io.Reader, maxFrameSize uint32) (*Message, error) {
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes, max %d: %w", size, maxFrameSize, ErrFrameTooLarge)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	m := &Message{}
+	if err := goproto.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("unmarshal message: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,65 @@
+package proto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func TestFraming_RoundTripThroughPipe(t *testing.T) {
+	messages := []*Message{
+		{SenderId: "a", Payload: &Message_Ping{Ping: &Ping{Timestamp: 1}}},
+		{SenderId: "b", Payload: &Message_Vote{Vote: &Vote{InstanceId: []byte{1, 2}, ChainId: 7, Vote: true}}},
+		{SenderId: "c"},
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		for _, m := range messages {
+			if err := WriteMessage(w, m); err != nil {
+				_ = w.CloseWithError(err)
+				return
+			}
+		}
+		_ = w.Close()
+	}()
+
+	for _, expected := range messages {
+		m, err := ReadMessage(r)
+		require.NoError(t, err)
+		assert.True(t, goproto.Equal(expected, m), "got %v, expected %v", m, expected)
+	}
+	_, err := ReadMessage(r)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestFraming_RejectsOversizedFrame(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMessage(&buf, &Message{SenderId: "a long enough sender id"}))
+
+	_, err := ReadMessageWithLimit(bytes.NewReader(buf.Bytes()), 4)
+	require.ErrorIs(t, err, ErrFrameTooLarge)
+
+	// A hostile length prefix is rejected before allocating the frame
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], DefaultMaxFrameSize+1)
+	_, err = ReadMessage(bytes.NewReader(header[:]))
+	require.ErrorIs(t, err, ErrFrameTooLarge)
+}
+
+func TestFraming_TruncatedFrame(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMessage(&buf, &Message{SenderId: "sender"}))
+	frame := buf.Bytes()
+
+	_, err := ReadMessage(bytes.NewReader(frame[:2]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = ReadMessage(bytes.NewReader(frame[:len(frame)-1]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,59 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+
+	goproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/compose-network/specs/compose"
+)
+
+var ErrInvalidInstanceIDLength = errors.New("invalid instance ID length")
+
+// instanceIDField is the name of the fields carrying a compose.InstanceID.
+const instanceIDField protoreflect.Name = "instance_id"
+
+// ValidateInstanceIDBytes checks that an instance_id field holds exactly the bytes of a compose.InstanceID,
+// returning ErrInvalidInstanceIDLength otherwise.
+func ValidateInstanceIDBytes(id []byte) error {
+	if expected := len(compose.InstanceID{}); len(id) != expected {
+		return fmt.Errorf("got %d bytes, expected %d: %w", len(id), expected, ErrInvalidInstanceIDLength)
+	}
+	return nil
+}
+
+// InstanceIDFromBytes converts an instance_id field to a compose.InstanceID,
+// rejecting ones that would be truncated or padded when copied.
+func InstanceIDFromBytes(id []byte) (compose.InstanceID, error) {
+	if err := ValidateInstanceIDBytes(id); err != nil {
+		return compose.InstanceID{}, err
+	}
+	return compose.InstanceID(id), nil
+}
+
+// ValidateInstanceIDs validates every instance_id field of the message, including those of nested messages
+// (e.g. the payload of a Message envelope), so that decoded messages can be checked as they're read from a stream.
+// Unset instance IDs are rejected as well.
+func ValidateInstanceIDs(m goproto.Message) error {
+	return validateInstanceIDs(m.ProtoReflect())
+}
+
+func validateInstanceIDs(m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		switch {
+		case fd.Name() == instanceIDField && fd.Kind() == protoreflect.BytesKind && !fd.IsList():
+			if err := ValidateInstanceIDBytes(m.Get(fd).Bytes()); err != nil {
+				return fmt.Errorf("%s: %w", fd.FullName(), err)
+			}
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() && m.Has(fd):
+			if err := validateInstanceIDs(m.Get(fd).Message()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/compose-network/specs/compose"
+)
+
+func TestValidateInstanceIDBytes(t *testing.T) {
+	require.NoError(t, ValidateInstanceIDBytes(make([]byte, 32)))
+	require.ErrorIs(t, ValidateInstanceIDBytes(nil), ErrInvalidInstanceIDLength)
+	require.ErrorIs(t, ValidateInstanceIDBytes([]byte{1}), ErrInvalidInstanceIDLength)
+	require.ErrorIs(t, ValidateInstanceIDBytes([]byte{1, 2, 3, 4}), ErrInvalidInstanceIDLength)
+	require.ErrorIs(t, ValidateInstanceIDBytes(make([]byte, 33)), ErrInvalidInstanceIDLength)
+}
+
+func TestInstanceIDFromBytes(t *testing.T) {
+	raw := bytes.Repeat([]byte{7}, 32)
+	id, err := InstanceIDFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, compose.InstanceID(raw), id)
+
+	_, err = InstanceIDFromBytes(raw[:4])
+	require.ErrorIs(t, err, ErrInvalidInstanceIDLength)
+	_, err = InstanceIDFromBytes(append(raw, 8))
+	require.ErrorIs(t, err, ErrInvalidInstanceIDLength)
+}
+
+func TestValidateInstanceIDs(t *testing.T) {
+	valid := make([]byte, 32)
+
+	require.NoError(t, ValidateInstanceIDs(&Vote{InstanceId: valid, ChainId: 1}))
+	require.NoError(t, ValidateInstanceIDs(WrapDecided("s", &Decided{InstanceId: valid})))
+	// Messages without instance ID
+	require.NoError(t, ValidateInstanceIDs(WrapPing("s", &Ping{Timestamp: 1})))
+
+	require.ErrorIs(t, ValidateInstanceIDs(&Vote{InstanceId: []byte{1}}), ErrInvalidInstanceIDLength)
+	require.ErrorIs(t, ValidateInstanceIDs(&Vote{}), ErrInvalidInstanceIDLength)
+	// Nested in the envelope payload
+	err := ValidateInstanceIDs(WrapStartInstance("s", &StartInstance{InstanceId: []byte{1, 2, 3, 4}}))
+	require.ErrorIs(t, err, ErrInvalidInstanceIDLength)
+	assert.Contains(t, err.Error(), "compose.StartInstance.instance_id")
+}
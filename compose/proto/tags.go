@@ -0,0 +1,92 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	goproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TypeTag identifies the concrete type of a protocol message sent without envelope.
+// Tags are stable: they match the field numbers of the Message payload oneof, and must never be reused.
+type TypeTag = uint16
+
+const (
+	TagHandshakeRequest  TypeTag = 2
+	TagHandshakeResponse TypeTag = 3
+	TagPing              TypeTag = 4
+	TagPong              TypeTag = 5
+	TagXTRequest         TypeTag = 6
+	TagStartInstance     TypeTag = 7
+	TagVote              TypeTag = 8
+	TagDecided           TypeTag = 9
+	TagMailboxMessage    TypeTag = 10
+	TagStartPeriod       TypeTag = 11
+	TagRollback          TypeTag = 12
+	TagProof             TypeTag = 13
+	TagNativeDecided     TypeTag = 14
+	TagWSDecided         TypeTag = 15
+)
+
+var (
+	ErrUnknownTypeTag     = errors.New("unknown message type tag")
+	ErrUnsupportedMessage = errors.New("message type has no type tag")
+)
+
+// messageTypes maps each type tag to a constructor of its message type.
+var messageTypes = map[TypeTag]func() goproto.Message{
+	TagHandshakeRequest:  func() goproto.Message { return &HandshakeRequest{} },
+	TagHandshakeResponse: func() goproto.Message { return &HandshakeResponse{} },
+	TagPing:              func() goproto.Message { return &Ping{} },
+	TagPong:              func() goproto.Message { return &Pong{} },
+	TagXTRequest:         func() goproto.Message { return &XTRequest{} },
+	TagStartInstance:     func() goproto.Message { return &StartInstance{} },
+	TagVote:              func() goproto.Message { return &Vote{} },
+	TagDecided:           func() goproto.Message { return &Decided{} },
+	TagMailboxMessage:    func() goproto.Message { return &MailboxMessage{} },
+	TagStartPeriod:       func() goproto.Message { return &StartPeriod{} },
+	TagRollback:          func() goproto.Message { return &Rollback{} },
+	TagProof:             func() goproto.Message { return &Proof{} },
+	TagNativeDecided:     func() goproto.Message { return &NativeDecided{} },
+	TagWSDecided:         func() goproto.Message { return &WSDecided{} },
+}
+
+// messageTags returns the reverse of messageTypes, by message full name.
+// It's built lazily, as message descriptors aren't initialized yet when package variables are.
+var messageTags = sync.OnceValue(func() map[protoreflect.FullName]TypeTag {
+	tags := make(map[protoreflect.FullName]TypeTag, len(messageTypes))
+	for tag, newMessage := range messageTypes {
+		tags[newMessage().ProtoReflect().Descriptor().FullName()] = tag
+	}
+	return tags
+})
+
+// EncodeFramed marshals a protocol message along with the type tag the receiver needs to decode it.
+// Messages without a type tag (e.g. the Message envelope) return ErrUnsupportedMessage.
+func EncodeFramed(m goproto.Message) (TypeTag, []byte, error) {
+	fullName := m.ProtoReflect().Descriptor().FullName()
+	tag, ok := messageTags()[fullName]
+	if !ok {
+		return 0, nil, fmt.Errorf("%s: %w", fullName, ErrUnsupportedMessage)
+	}
+	data, err := goproto.Marshal(m)
+	if err != nil {
+		return 0, nil, fmt.Errorf("marshal %s: %w", fullName, err)
+	}
+	return tag, data, nil
+}
+
+// DecodeFramed unmarshals data into a new message of the type identified by typeTag.
+func DecodeFramed(typeTag TypeTag, data []byte) (goproto.Message, error) {
+	newMessage, ok := messageTypes[typeTag]
+	if !ok {
+		return nil, fmt.Errorf("tag %d: %w", typeTag, ErrUnknownTypeTag)
+	}
+	m := newMessage()
+	if err := goproto.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("unmarshal tag %d: %w", typeTag, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,68 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func TestFramed_RoundTrip(t *testing.T) {
+	messages := []goproto.Message{
+		&HandshakeRequest{Timestamp: 1, PublicKey: []byte{2}, Signature: []byte{3}, ClientId: "c", Nonce: []byte{4}},
+		&HandshakeResponse{Accepted: true, Error: "e", SessionId: "s"},
+		&Ping{Timestamp: 5},
+		&Pong{Timestamp: 6},
+		&XTRequest{TransactionRequests: []*TransactionRequest{{ChainId: 1, Transaction: [][]byte{{7}}}}},
+		&StartInstance{InstanceId: []byte{1}, PeriodId: 2, SequenceNumber: 3, XtRequest: &XTRequest{}},
+		&Vote{InstanceId: []byte{1}, ChainId: 2, Vote: true},
+		&Decided{InstanceId: []byte{1}, Decision: true},
+		&MailboxMessage{SessionId: 1, SourceChain: 2, DestinationChain: 3, Label: "l", Data: [][]byte{{8}}},
+		&StartPeriod{PeriodId: 1, SuperblockNumber: 2},
+		&Rollback{PeriodId: 1, LastFinalizedSuperblockNumber: 2, LastFinalizedSuperblockHash: []byte{3}},
+		&Proof{PeriodId: 1, SuperblockNumber: 2, ProofData: []byte{3}},
+		&NativeDecided{InstanceId: []byte{1}, Decision: true},
+		&WSDecided{InstanceId: []byte{1}, Decision: true},
+	}
+	require.Len(t, messages, len(messageTypes), "every tagged type is covered")
+
+	seen := make(map[TypeTag]struct{})
+	for _, m := range messages {
+		tag, data, err := EncodeFramed(m)
+		require.NoError(t, err)
+		seen[tag] = struct{}{}
+
+		decoded, err := DecodeFramed(tag, data)
+		require.NoError(t, err)
+		assert.True(t, goproto.Equal(m, decoded), "round trip of %T", m)
+	}
+	assert.Len(t, seen, len(messages))
+}
+
+func TestFramed_TagsMatchEnvelopeFields(t *testing.T) {
+	payload := (&Message{}).ProtoReflect().Descriptor().Oneofs().ByName("payload")
+	require.NotNil(t, payload)
+
+	fields := payload.Fields()
+	require.Equal(t, len(messageTypes), fields.Len())
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		tag, ok := messageTags()[field.Message().FullName()]
+		require.True(t, ok, "%s has a tag", field.Message().FullName())
+		assert.Equal(t, TypeTag(field.Number()), tag)
+	}
+}
+
+func TestFramed_Errors(t *testing.T) {
+	_, _, err := EncodeFramed(&Message{})
+	require.ErrorIs(t, err, ErrUnsupportedMessage)
+	_, _, err = EncodeFramed(&TransactionRequest{})
+	require.ErrorIs(t, err, ErrUnsupportedMessage)
+
+	_, err = DecodeFramed(0, nil)
+	require.ErrorIs(t, err, ErrUnknownTypeTag)
+
+	_, err = DecodeFramed(TagVote, []byte{0xff})
+	require.Error(t, err)
+}
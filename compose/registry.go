@@ -0,0 +1,59 @@
+package compose
+
+import "sync"
+
+// InstanceRegistry is a concurrency-safe registry of live instance drivers (e.g. SCP publisher
+// or sequencer instances), keyed by instance ID. The zero value is empty and ready to use.
+type InstanceRegistry[T any] struct {
+	mu        sync.RWMutex
+	instances map[InstanceID]T
+}
+
+// Store registers the value for the instance, replacing any previous one.
+func (r *InstanceRegistry[T]) Store(id InstanceID, value T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.instances == nil {
+		r.instances = make(map[InstanceID]T)
+	}
+	r.instances[id] = value
+}
+
+// Load returns the value registered for the instance, if any.
+func (r *InstanceRegistry[T]) Load(id InstanceID) (T, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	value, ok := r.instances[id]
+	return value, ok
+}
+
+// Delete removes the instance from the registry. It's a no-op for unknown instances.
+func (r *InstanceRegistry[T]) Delete(id InstanceID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.instances, id)
+}
+
+// Len returns the number of registered instances.
+func (r *InstanceRegistry[T]) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.instances)
+}
+
+// Range calls f for each registered instance, in no particular order, until f returns false.
+// It iterates over a copy of the registry, so f may safely call back into it.
+func (r *InstanceRegistry[T]) Range(f func(id InstanceID, value T) bool) {
+	r.mu.RLock()
+	entries := make(map[InstanceID]T, len(r.instances))
+	for id, value := range r.instances {
+		entries[id] = value
+	}
+	r.mu.RUnlock()
+
+	for id, value := range entries {
+		if !f(id, value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package compose
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceRegistry_StoreLoadDelete(t *testing.T) {
+	var registry InstanceRegistry[string]
+	_, ok := registry.Load(InstanceID{1})
+	assert.False(t, ok)
+
+	registry.Store(InstanceID{1}, "a")
+	registry.Store(InstanceID{1}, "b")
+	value, ok := registry.Load(InstanceID{1})
+	require.True(t, ok)
+	assert.Equal(t, "b", value)
+	assert.Equal(t, 1, registry.Len())
+
+	registry.Delete(InstanceID{1})
+	registry.Delete(InstanceID{2})
+	_, ok = registry.Load(InstanceID{1})
+	assert.False(t, ok)
+	assert.Equal(t, 0, registry.Len())
+}
+
+func TestInstanceRegistry_Concurrent(t *testing.T) {
+	var registry InstanceRegistry[int]
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := InstanceID{byte(i)}
+			registry.Store(id, i)
+			value, ok := registry.Load(id)
+			assert.True(t, ok)
+			assert.Equal(t, i, value)
+			registry.Range(func(InstanceID, int) bool { return true })
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 50, registry.Len())
+}
+
+func TestInstanceRegistry_RangeStopsEarly(t *testing.T) {
+	var registry InstanceRegistry[int]
+	for i := range 5 {
+		registry.Store(InstanceID{byte(i)}, i)
+	}
+
+	visited := 0
+	registry.Range(func(id InstanceID, value int) bool {
+		visited++
+		// Calling back into the registry doesn't deadlock
+		registry.Delete(id)
+		return visited < 2
+	})
+	assert.Equal(t, 2, visited)
+	assert.Equal(t, 3, registry.Len())
+}
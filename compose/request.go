@@ -0,0 +1,57 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDuplicatedChain          = errors.New("duplicated chain in request")
+	ErrEmptyRequest             = NewError(ErrNoTransactions, "request has no chains")
+	ErrChainWithoutTransactions = NewError(ErrNoTransactions, "chain without transactions in request")
+)
+
+// Validate checks that the request has at least one chain, that no chain appears twice,
+// and that every chain has at least one transaction.
+func (r XTRequest) Validate() error {
+	if len(r.Transactions) == 0 {
+		return ErrEmptyRequest
+	}
+	seen := make(map[ChainID]struct{}, len(r.Transactions))
+	for _, txReq := range r.Transactions {
+		if _, ok := seen[txReq.ChainID]; ok {
+			return fmt.Errorf("chain %d: %w", txReq.ChainID, ErrDuplicatedChain)
+		}
+		seen[txReq.ChainID] = struct{}{}
+		if len(txReq.Transactions) == 0 {
+			return fmt.Errorf("chain %d: %w", txReq.ChainID, ErrChainWithoutTransactions)
+		}
+	}
+	return nil
+}
+
+// XTRequestBuilder builds a request chain by chain, validating it on Build.
+type XTRequestBuilder struct {
+	request XTRequest
+}
+
+func NewXTRequestBuilder() *XTRequestBuilder {
+	return &XTRequestBuilder{}
+}
+
+// AddChain adds the transactions of a chain to the request. The transactions are copied.
+func (b *XTRequestBuilder) AddChain(chainID ChainID, txs ...[]byte) *XTRequestBuilder {
+	b.request.Transactions = append(b.request.Transactions, TransactionRequest{
+		ChainID:      chainID,
+		Transactions: CloneByteSlices(txs),
+	})
+	return b
+}
+
+// Build returns a copy of the built request, or the Validate error if it's invalid.
+func (b *XTRequestBuilder) Build() (XTRequest, error) {
+	if err := b.request.Validate(); err != nil {
+		return XTRequest{}, err
+	}
+	return b.request.Clone(), nil
+}
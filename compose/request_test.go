@@ -0,0 +1,46 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXTRequestBuilder_Build(t *testing.T) {
+	tx := []byte("tx-1")
+	request, err := NewXTRequestBuilder().
+		AddChain(ChainID(1), tx, []byte("tx-2")).
+		AddChain(ChainID(2), []byte("tx-3")).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, XTRequest{
+		Transactions: []TransactionRequest{
+			{ChainID: 1, Transactions: [][]byte{[]byte("tx-1"), []byte("tx-2")}},
+			{ChainID: 2, Transactions: [][]byte{[]byte("tx-3")}},
+		},
+	}, request)
+
+	// The request doesn't alias the given transactions
+	tx[0] = 'X'
+	assert.Equal(t, []byte("tx-1"), request.Transactions[0].Transactions[0])
+}
+
+func TestXTRequestBuilder_RejectsInvalid(t *testing.T) {
+	_, err := NewXTRequestBuilder().
+		AddChain(ChainID(1), []byte("a")).
+		AddChain(ChainID(2), []byte("b")).
+		AddChain(ChainID(1), []byte("c")).
+		Build()
+	require.ErrorIs(t, err, ErrDuplicatedChain)
+
+	_, err = NewXTRequestBuilder().
+		AddChain(ChainID(1), []byte("a")).
+		AddChain(ChainID(2)).
+		Build()
+	require.ErrorIs(t, err, ErrChainWithoutTransactions)
+	require.ErrorIs(t, err, ErrNoTransactions)
+
+	_, err = NewXTRequestBuilder().Build()
+	require.ErrorIs(t, err, ErrEmptyRequest)
+}
@@ -1,6 +1,21 @@
 package sbcp
 
-import "github.com/compose-network/specs/compose"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/compose-network/specs/compose"
+)
+
+var ErrInvalidSuperblockChain = errors.New("sealed blocks do not form a valid superblock chain")
+
+const (
+	// EncodedBlockHeaderSize is the size of the BlockHeader encoding.
+	EncodedBlockHeaderSize = 8 + 32 + 32
+	// EncodedSealedBlockHeaderSize is the size of the SealedBlockHeader encoding.
+	EncodedSealedBlockHeaderSize = EncodedBlockHeaderSize + 8 + 8
+)
 
 type BlockNumber uint64
 
@@ -16,6 +31,17 @@ type BlockHeader struct {
 	StateRoot compose.StateRoot
 }
 
+// Encode returns the canonical fixed-layout encoding of the header (e.g. as a proof input):
+// the block number as 8 bytes big-endian, followed by the 32-byte block hash and the 32-byte state root.
+// The layout is stable and must not change.
+func (h BlockHeader) Encode() []byte {
+	buf := make([]byte, 0, EncodedBlockHeaderSize)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.Number))
+	buf = append(buf, h.BlockHash[:]...)
+	buf = append(buf, h.StateRoot[:]...)
+	return buf
+}
+
 // SealedBlockHeader represents a block that has been sealed and included in the superblock chain.
 type SealedBlockHeader struct {
 	BlockHeader      BlockHeader
@@ -23,8 +49,49 @@ type SealedBlockHeader struct {
 	SuperblockNumber compose.SuperblockNumber
 }
 
+// Encode returns the canonical fixed-layout encoding of the sealed header (e.g. as a proof input):
+// the BlockHeader encoding, followed by the period ID and the superblock number as 8 bytes big-endian each.
+// The layout is stable and must not change.
+func (h SealedBlockHeader) Encode() []byte {
+	buf := make([]byte, 0, EncodedSealedBlockHeaderSize)
+	buf = append(buf, h.BlockHeader.Encode()...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.PeriodID))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.SuperblockNumber))
+	return buf
+}
+
 type SettledState struct {
 	BlockHeader      BlockHeader
 	SuperblockNumber compose.SuperblockNumber
 	SuperblockHash   compose.SuperblockHash
 }
+
+// VerifySuperblockChain checks that the sealed blocks, in chain order, form a valid chain:
+// block numbers are sequential, periods never go back, blocks of the same period belong to the same superblock,
+// and superblock numbers increase with periods (by exactly one between consecutive periods).
+// It returns the first inconsistency found, wrapping ErrInvalidSuperblockChain.
+func VerifySuperblockChain(headers []SealedBlockHeader) error {
+	for i := 1; i < len(headers); i++ {
+		prev, curr := headers[i-1], headers[i]
+		if curr.BlockHeader.Number != prev.BlockHeader.Number+1 {
+			return fmt.Errorf("block %d follows block %d: %w",
+				curr.BlockHeader.Number, prev.BlockHeader.Number, ErrInvalidSuperblockChain)
+		}
+
+		switch {
+		case curr.PeriodID < prev.PeriodID:
+			return fmt.Errorf("block %d period %d is before block %d period %d: %w",
+				curr.BlockHeader.Number, curr.PeriodID, prev.BlockHeader.Number, prev.PeriodID,
+				ErrInvalidSuperblockChain)
+		case curr.PeriodID == prev.PeriodID && curr.SuperblockNumber != prev.SuperblockNumber:
+			return fmt.Errorf("block %d superblock %d differs from superblock %d in the same period %d: %w",
+				curr.BlockHeader.Number, curr.SuperblockNumber, prev.SuperblockNumber, curr.PeriodID,
+				ErrInvalidSuperblockChain)
+		case curr.PeriodID > prev.PeriodID && (curr.SuperblockNumber <= prev.SuperblockNumber ||
+			(curr.PeriodID == prev.PeriodID+1 && curr.SuperblockNumber != prev.SuperblockNumber+1)):
+			return fmt.Errorf("block %d superblock %d does not follow superblock %d: %w",
+				curr.BlockHeader.Number, curr.SuperblockNumber, prev.SuperblockNumber, ErrInvalidSuperblockChain)
+		}
+	}
+	return nil
+}
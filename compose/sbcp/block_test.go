@@ -0,0 +1,92 @@
+package sbcp
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/compose-network/specs/compose"
+)
+
+func TestSealedBlockHeader_Encode(t *testing.T) {
+	header := SealedBlockHeader{
+		BlockHeader: BlockHeader{
+			Number:    1,
+			BlockHash: compose.BlockHash{0xaa},
+			StateRoot: compose.StateRoot{0xbb},
+		},
+		PeriodID:         2,
+		SuperblockNumber: 3,
+	}
+
+	encoded := header.Encode()
+	require.Len(t, encoded, EncodedSealedBlockHeaderSize)
+	assert.Equal(t,
+		"0000000000000001"+"aa"+strings.Repeat("00", 31)+"bb"+strings.Repeat("00", 31)+
+			"0000000000000002"+"0000000000000003",
+		hex.EncodeToString(encoded))
+	assert.Equal(t, header.BlockHeader.Encode(), encoded[:EncodedBlockHeaderSize])
+
+	// Equal headers encode identically
+	same := header
+	assert.Equal(t, encoded, same.Encode())
+
+	// Any single field change alters the encoding
+	changes := []func(h *SealedBlockHeader){
+		func(h *SealedBlockHeader) { h.BlockHeader.Number++ },
+		func(h *SealedBlockHeader) { h.BlockHeader.BlockHash[31] = 1 },
+		func(h *SealedBlockHeader) { h.BlockHeader.StateRoot[31] = 1 },
+		func(h *SealedBlockHeader) { h.PeriodID++ },
+		func(h *SealedBlockHeader) { h.SuperblockNumber++ },
+	}
+	for i, change := range changes {
+		changed := header
+		change(&changed)
+		assert.NotEqual(t, encoded, changed.Encode(), "change %d", i)
+	}
+}
+
+func TestVerifySuperblockChain(t *testing.T) {
+	sealed := func(
+		number BlockNumber,
+		periodID compose.PeriodID,
+		superblock compose.SuperblockNumber,
+	) SealedBlockHeader {
+		return SealedBlockHeader{
+			BlockHeader:      BlockHeader{Number: number},
+			PeriodID:         periodID,
+			SuperblockNumber: superblock,
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, VerifySuperblockChain(nil))
+		require.NoError(t, VerifySuperblockChain([]SealedBlockHeader{
+			sealed(10, 3, 7),
+			sealed(11, 3, 7),
+			sealed(12, 4, 8),
+			// Periods without blocks in between
+			sealed(13, 7, 10),
+		}))
+	})
+
+	tests := []struct {
+		name    string
+		headers []SealedBlockHeader
+	}{
+		{"block_gap", []SealedBlockHeader{sealed(10, 3, 7), sealed(12, 4, 8)}},
+		{"block_reorder", []SealedBlockHeader{sealed(11, 4, 8), sealed(10, 3, 7)}},
+		{"period_regression", []SealedBlockHeader{sealed(10, 4, 8), sealed(11, 3, 8)}},
+		{"superblock_change_within_period", []SealedBlockHeader{sealed(10, 3, 7), sealed(11, 3, 8)}},
+		{"superblock_gap", []SealedBlockHeader{sealed(10, 3, 7), sealed(11, 4, 9)}},
+		{"superblock_regression", []SealedBlockHeader{sealed(10, 3, 7), sealed(11, 5, 7)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, VerifySuperblockChain(tt.headers), ErrInvalidSuperblockChain)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package sbcp
 
 import (
 	"context"
+	"time"
 
 	"github.com/compose-network/specs/compose"
 )
@@ -43,11 +44,17 @@ type fakePublisherMessenger struct {
 		SuperblockNumber compose.SuperblockNumber
 	}
 	startInstances []compose.Instance
-	rollbacks      []struct {
+	// Optional error returned by BroadcastStartInstance
+	startInstanceErr error
+	// Optional callback run on each start instance broadcast
+	onStartInstance func()
+	rollbacks       []struct {
 		PeriodID         compose.PeriodID
 		SuperblockNumber compose.SuperblockNumber
 		SuperblockHash   compose.SuperblockHash
 	}
+	// Optional callback run on each rollback broadcast
+	onRollback func()
 }
 
 func (m *fakePublisherMessenger) BroadcastStartPeriod(p compose.PeriodID, t compose.SuperblockNumber) {
@@ -57,8 +64,15 @@ func (m *fakePublisherMessenger) BroadcastStartPeriod(p compose.PeriodID, t comp
 	}{p, t})
 }
 
-func (m *fakePublisherMessenger) BroadcastStartInstance(inst compose.Instance) {
+func (m *fakePublisherMessenger) BroadcastStartInstance(inst compose.Instance) error {
+	if m.onStartInstance != nil {
+		m.onStartInstance()
+	}
+	if m.startInstanceErr != nil {
+		return m.startInstanceErr
+	}
 	m.startInstances = append(m.startInstances, inst)
+	return nil
 }
 
 func (m *fakePublisherMessenger) BroadcastRollback(
@@ -71,6 +85,9 @@ func (m *fakePublisherMessenger) BroadcastRollback(
 		SuperblockNumber compose.SuperblockNumber
 		SuperblockHash   compose.SuperblockHash
 	}{p, s, h})
+	if m.onRollback != nil {
+		m.onRollback()
+	}
 }
 
 type fakePublisherProver struct {
@@ -81,6 +98,8 @@ type fakePublisherProver struct {
 	}
 	nextProof []byte
 	err       error
+	// Optional callback run on each request, before returning
+	onRequest func()
 }
 
 func (p *fakePublisherProver) RequestSuperblockProof(
@@ -97,6 +116,9 @@ func (p *fakePublisherProver) RequestSuperblockProof(
 		hash       compose.SuperblockHash
 		proofs     [][]byte
 	}{superblockNumber, hash, copied})
+	if p.onRequest != nil {
+		p.onRequest()
+	}
 	if p.err != nil {
 		return nil, p.err
 	}
@@ -124,6 +146,10 @@ type fakeSequencerProver struct {
 		sb  compose.SuperblockNumber
 	}
 	nextProof []byte
+	// Optional error returned instead of the proof
+	err error
+	// Optional hook run while the proof is being generated
+	onRequest func()
 }
 
 func (p *fakeSequencerProver) RequestProofs(
@@ -135,12 +161,19 @@ func (p *fakeSequencerProver) RequestProofs(
 		hdr *BlockHeader
 		sb  compose.SuperblockNumber
 	}{hdr, sb})
+	if p.onRequest != nil {
+		p.onRequest()
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
 	return append([]byte(nil), p.nextProof...), nil
 }
 
 type fakeSequencerMessenger struct {
-	requests []compose.XTRequest
-	proofs   []struct {
+	requests   []compose.XTRequest
+	forwardErr error
+	proofs     []struct {
 		periodID         compose.PeriodID
 		superblockNumber compose.SuperblockNumber
 		proof            []byte
@@ -148,6 +181,9 @@ type fakeSequencerMessenger struct {
 }
 
 func (m *fakeSequencerMessenger) ForwardRequest(_ context.Context, request compose.XTRequest) error {
+	if m.forwardErr != nil {
+		return m.forwardErr
+	}
 	m.requests = append(m.requests, request)
 	return nil
 }
@@ -202,3 +238,48 @@ func makeDefaultChainSet() map[compose.ChainID]struct{} {
 		compose.ChainID(10),
 	)
 }
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// fakeProofMetrics records proof publication latencies.
+type fakeProofMetrics struct {
+	publications []struct {
+		superblock compose.SuperblockNumber
+		waited     time.Duration
+	}
+}
+
+func (m *fakeProofMetrics) RecordPublication(superblock compose.SuperblockNumber, waited time.Duration) {
+	m.publications = append(m.publications, struct {
+		superblock compose.SuperblockNumber
+		waited     time.Duration
+	}{superblock, waited})
+}
+
+// countingPublisherMetrics counts each settlement outcome.
+type countingPublisherMetrics struct {
+	rollbacks      int
+	proofsReceived map[compose.ChainID]int
+	networkProofs  int
+	publications   int
+}
+
+func (m *countingPublisherMetrics) IncRollback() { m.rollbacks++ }
+
+func (m *countingPublisherMetrics) IncProofReceived(chainID compose.ChainID) {
+	if m.proofsReceived == nil {
+		m.proofsReceived = make(map[compose.ChainID]int)
+	}
+	m.proofsReceived[chainID]++
+}
+
+func (m *countingPublisherMetrics) IncNetworkProof() { m.networkProofs++ }
+
+func (m *countingPublisherMetrics) IncPublication() { m.publications++ }
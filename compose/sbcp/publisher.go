@@ -1,9 +1,12 @@
 package sbcp
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -16,6 +19,9 @@ var (
 	ErrChainNotActive      = errors.New("chain not active")
 	ErrOldSettledState     = errors.New("can not advance to older settled state")
 	ErrInvalidRequest      = errors.New("invalid request")
+	ErrRollbackMismatch    = errors.New("rollback does not match last finalized state")
+	ErrNotOldestPending    = errors.New("superblock is not the oldest pending one")
+	ErrUnknownChain        = errors.New("chain not in the configured chains")
 )
 
 type Publisher interface {
@@ -23,8 +29,20 @@ type Publisher interface {
 	StartPeriod() error
 	// StartInstance is called by the upper layer to try starting a new instance from the queued requests.
 	StartInstance(req compose.XTRequest) (compose.Instance, error)
+	// StartAndBroadcastInstance starts a new instance as StartInstance does, and broadcasts it to its participants.
+	// If the broadcast fails, the instance is discarded and its chains released.
+	StartAndBroadcastInstance(req compose.XTRequest) (compose.Instance, error)
+	// QueueRequest adds a request to the publisher's FIFO queue of pending requests.
+	QueueRequest(req compose.XTRequest) error
+	// TryStartQueued starts as many queued requests as possible, skipping those that conflict with active chains.
+	TryStartQueued() []compose.Instance
+	// CancelQueued removes the queued request with the given fingerprint (see RequestFingerprint),
+	// returning whether it was found.
+	CancelQueued(fingerprint [32]byte) bool
 	// DecideInstance is called once an instance gets decided.
 	DecideInstance(instance compose.Instance) error
+	// HighestDecidedSequence returns the highest sequence number decided in the current period (0 if none).
+	HighestDecidedSequence() compose.SequenceNumber
 	// AdvanceSettledState is called when L1 emits a new settled state event
 	AdvanceSettledState(
 		superblockNumber compose.SuperblockNumber,
@@ -32,14 +50,159 @@ type Publisher interface {
 	) error
 	// ProofTimeout: Once a period starts, if the network ZK proof is not generated within 9 epochs,
 	// the publisher must roll back to the last finalized superblock and discard any active settlement pipeline.
-	ProofTimeout()
+	// The timed out superblock must be the oldest pending one, otherwise ErrNotOldestPending is returned.
+	ProofTimeout(superblockNumber compose.SuperblockNumber) error
+	// ShouldRollback reports whether the oldest pending superblock has exceeded the proof window,
+	// so that the upper layer can poll it and call ProofTimeout. Always false if ProofWindow is 0.
+	ShouldRollback() bool
+	// ReceiveRollback is called whenever a rollback broadcast is received from a publisher.
+	// Rollbacks originated by this publisher (e.g. looped back through gossip) are ignored.
+	ReceiveRollback(
+		periodID compose.PeriodID,
+		superblockNumber compose.SuperblockNumber,
+		superblockHash compose.SuperblockHash,
+	) error
 	// ReceiveProof is called whenever a proof is received from a sequencer.
 	ReceiveProof(
 		periodID compose.PeriodID,
 		superblockNumber compose.SuperblockNumber,
 		proof []byte,
 		chainID compose.ChainID,
-	)
+	) ProofAck
+	// LastProofLatency returns the time from the first proof received to the L1 publication
+	// of the last published superblock.
+	LastProofLatency() time.Duration
+	// Snapshot returns a deep copy of the publisher state.
+	Snapshot() PublisherSnapshot
+	// ImportProofs merges the proofs buffered in another publisher's snapshot into the local ones,
+	// returning how many were imported, and publishes the next superblock if it gets all its proofs.
+	ImportProofs(snapshot PublisherSnapshot) int
+}
+
+// ProofAck acknowledges a received sequencer proof, telling whether it was accepted or why it was ignored.
+type ProofAck int
+
+const (
+	ProofAckAccepted ProofAck = iota
+	ProofAckIgnoredOld
+	ProofAckIgnoredNotTerminated
+	ProofAckIgnoredNotNext
+	ProofAckIgnoredWrongPeriod
+	ProofAckIgnoredDuplicate
+	ProofAckIgnoredInvalid
+	ProofAckIgnoredAlreadyAggregated
+)
+
+func (a ProofAck) String() string {
+	switch a {
+	case ProofAckAccepted:
+		return "Accepted"
+	case ProofAckIgnoredOld:
+		return "IgnoredOld"
+	case ProofAckIgnoredNotTerminated:
+		return "IgnoredNotTerminated"
+	case ProofAckIgnoredNotNext:
+		return "IgnoredNotNext"
+	case ProofAckIgnoredWrongPeriod:
+		return "IgnoredWrongPeriod"
+	case ProofAckIgnoredDuplicate:
+		return "IgnoredDuplicate"
+	case ProofAckIgnoredInvalid:
+		return "IgnoredInvalid"
+	case ProofAckIgnoredAlreadyAggregated:
+		return "IgnoredAlreadyAggregated"
+	default:
+		return "Unknown"
+	}
+}
+
+// ProofVerifier verifies a sequencer proof before it's stored for aggregation.
+type ProofVerifier func(superblockNumber compose.SuperblockNumber, chainID compose.ChainID, proof []byte) error
+
+// ProofMetrics records settlement metrics for capacity planning.
+type ProofMetrics interface {
+	// RecordPublication is called right before publishing a network proof to L1,
+	// with the time waited since the first sequencer proof for the superblock was received.
+	RecordPublication(superblock compose.SuperblockNumber, waited time.Duration)
+}
+
+// PublisherMetrics counts settlement outcomes, e.g. backing Prometheus counters.
+type PublisherMetrics interface {
+	// IncRollback is called whenever the publisher rolls back to the last finalized superblock.
+	IncRollback()
+	// IncProofReceived is called whenever a sequencer proof is accepted.
+	IncProofReceived(chainID compose.ChainID)
+	// IncNetworkProof is called whenever a network proof is generated.
+	IncNetworkProof()
+	// IncPublication is called whenever a network proof is published to L1.
+	IncPublication()
+}
+
+// noopPublisherMetrics is the default PublisherMetrics, discarding every count.
+type noopPublisherMetrics struct{}
+
+func (noopPublisherMetrics) IncRollback()                     {}
+func (noopPublisherMetrics) IncProofReceived(compose.ChainID) {}
+func (noopPublisherMetrics) IncNetworkProof()                 {}
+func (noopPublisherMetrics) IncPublication()                  {}
+
+// EquivocationHook is called when a chain submits a second, different proof for the same superblock.
+type EquivocationHook func(chainID compose.ChainID, superblockNumber compose.SuperblockNumber)
+
+// PublisherOption configures optional behavior of the publisher.
+type PublisherOption func(*publisher)
+
+// WithProofMetrics sets the metrics recorder for proof publication latencies.
+func WithProofMetrics(metrics ProofMetrics) PublisherOption {
+	return func(p *publisher) {
+		p.proofMetrics = metrics
+	}
+}
+
+// WithPublisherMetrics sets the counters of rollbacks, received proofs, network proofs and publications.
+func WithPublisherMetrics(metrics PublisherMetrics) PublisherOption {
+	return func(p *publisher) {
+		p.metrics = metrics
+	}
+}
+
+// WithClock overrides the clock used to measure latencies (time.Now by default).
+func WithClock(now func() time.Time) PublisherOption {
+	return func(p *publisher) {
+		p.now = now
+	}
+}
+
+// WithProofVerifier sets a verifier called on each received proof before storing it.
+// Proofs failing verification are ignored with ProofAckIgnoredInvalid and don't count towards aggregation.
+func WithProofVerifier(verifier ProofVerifier) PublisherOption {
+	return func(p *publisher) {
+		p.proofVerifier = verifier
+	}
+}
+
+// WithLogRequestBytes enables debug logs of each started request's transactions
+// (per-chain transaction counts and byte sizes), to help reconstruct what was submitted.
+func WithLogRequestBytes() PublisherOption {
+	return func(p *publisher) {
+		p.logRequestBytes = true
+	}
+}
+
+// WithOnEquivocation sets a hook fired, outside the publisher lock, whenever a chain submits a proof
+// that differs from the one already stored for the same superblock. The stored proof is kept.
+func WithOnEquivocation(hook EquivocationHook) PublisherOption {
+	return func(p *publisher) {
+		p.onEquivocation = hook
+	}
+}
+
+// WithChainFilter restricts the chains that requests may touch, on top of the configured chains.
+// StartInstance and QueueRequest reject requests touching chains not permitted with compose.ErrChainNotPermitted.
+func WithChainFilter(filter compose.ChainFilter) PublisherOption {
+	return func(p *publisher) {
+		p.chainFilter = filter
+	}
 }
 
 type PublisherProver interface {
@@ -53,6 +216,7 @@ type PublisherProver interface {
 
 type PublisherMessenger interface {
 	BroadcastStartPeriod(periodID compose.PeriodID, targetSuperblockNumber compose.SuperblockNumber)
+	BroadcastStartInstance(instance compose.Instance) error
 	BroadcastRollback(
 		periodID compose.PeriodID,
 		superblockNumber compose.SuperblockNumber,
@@ -73,24 +237,55 @@ type PublisherState struct {
 	LastFinalizedSuperblockHash   compose.SuperblockHash
 	Proofs                        map[compose.SuperblockNumber]map[compose.ChainID][]byte
 	Chains                        map[compose.ChainID]struct{}
+	// Time at which the first proof for each pending superblock was received
+	FirstProofReceivedAt map[compose.SuperblockNumber]time.Time
+	// Time from first proof received to L1 publication for the last published superblock
+	LastPublishedProofLatency time.Duration
+	// Superblocks whose sequencer proofs were already aggregated, waiting to be finalized on L1
+	AggregatedSuperblocks map[compose.SuperblockNumber]struct{}
 
 	// Instances scheduling
 	SequenceNumber compose.SequenceNumber   // Per-period sequence counter (monotone)
 	ActiveChains   map[compose.ChainID]bool // Chains with active instances
+	RequestQueue   []compose.XTRequest      // FIFO queue of requests waiting to be started
+	// Instances started by this publisher and not decided yet (not restored from snapshots)
+	ActiveInstances map[compose.InstanceID]struct{}
+	// Highest sequence number decided in the current period (0 if none)
+	HighestDecidedSequenceNumber compose.SequenceNumber
 
 	// Proof window duration (in number of superblocks/periods) through which a pending superblock can be proven.
 	// StartPeriods are rejected if the next superblock is bigger than LastFinalizedSuperblockNumber + ProofWindow.
 	// 0 value means no window constrain.
 	ProofWindow uint64
 
+	// Last rollback broadcast by this publisher, used to detect its own rollbacks looping back.
+	LastRollback *RollbackRecord
+
 	logger zerolog.Logger
 }
 
+// RollbackRecord identifies a rollback broadcast.
+type RollbackRecord struct {
+	PeriodID         compose.PeriodID
+	SuperblockNumber compose.SuperblockNumber
+	SuperblockHash   compose.SuperblockHash
+}
+
 type publisher struct {
-	mu        sync.Mutex
-	prover    PublisherProver
-	messenger PublisherMessenger
-	l1        L1
+	mu            sync.Mutex
+	prover        PublisherProver
+	messenger     PublisherMessenger
+	l1            L1
+	proofVerifier ProofVerifier // optional
+	proofMetrics  ProofMetrics  // optional
+	metrics       PublisherMetrics
+	now           func() time.Time
+	// Optional hook for chains submitting conflicting proofs
+	onEquivocation EquivocationHook
+	// Whether to log the transactions of each started request at debug level
+	logRequestBytes bool
+	// Chains that requests may touch, on top of the configured chains (all by default)
+	chainFilter compose.ChainFilter
 	PublisherState
 }
 
@@ -108,16 +303,19 @@ func NewPublisher(
 	proofWindow uint64,
 	logger zerolog.Logger,
 	chains map[compose.ChainID]struct{},
+	opts ...PublisherOption,
 ) (Publisher, error) {
 	if previousTargetSuperblockNumber < lastFinalizedSuperblockNumber {
 		return nil, errors.New("target superblock is less than the last finalized one")
 	}
 
-	return &publisher{
+	p := &publisher{
 		mu:        sync.Mutex{},
 		prover:    prover,
 		messenger: messenger,
 		l1:        l1,
+		metrics:   noopPublisherMetrics{},
+		now:       time.Now,
 		PublisherState: PublisherState{
 			PeriodID:               previousPeriodID,
 			TargetSuperblockNumber: previousTargetSuperblockNumber,
@@ -127,16 +325,25 @@ func NewPublisher(
 			LastFinalizedSuperblockHash:   lastFinalizedSuperblockHash,
 			Proofs:                        make(map[compose.SuperblockNumber]map[compose.ChainID][]byte),
 			Chains:                        chains,
+			FirstProofReceivedAt:          make(map[compose.SuperblockNumber]time.Time),
+			AggregatedSuperblocks:         make(map[compose.SuperblockNumber]struct{}),
 
 			// Instances scheduling
-			SequenceNumber: 0,
-			ActiveChains:   make(map[compose.ChainID]bool),
+			SequenceNumber:  0,
+			ActiveChains:    make(map[compose.ChainID]bool),
+			RequestQueue:    make([]compose.XTRequest, 0),
+			ActiveInstances: make(map[compose.InstanceID]struct{}),
 
 			ProofWindow: proofWindow,
 
 			logger: logger,
 		},
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
 }
 
 // StartPeriod is called whenever a new period starts (i.e. CurrEthereumEpoch % 10 == 0).
@@ -150,11 +357,9 @@ func (p *publisher) StartPeriod() error {
 	// Proof window constrain
 	// If the oldest pending superblock is older than ProofWindow, reject starting the new period
 	// as the upper layer should have called ProofTimeout already.
-	if p.ProofWindow != 0 { // 0 means no constrain
-		if nextSuperblock > p.LastFinalizedSuperblockNumber+compose.SuperblockNumber(1+p.ProofWindow) {
-			return fmt.Errorf("target superblock is %d, expected %d: %w",
-				p.TargetSuperblockNumber, p.LastFinalizedSuperblockNumber+1, ErrCannotStartPeriod)
-		}
+	if p.proofWindowExceeded(nextSuperblock) {
+		return fmt.Errorf("target superblock is %d, expected %d: %w",
+			p.TargetSuperblockNumber, p.LastFinalizedSuperblockNumber+1, ErrCannotStartPeriod)
 	}
 
 	p.PeriodID++
@@ -168,26 +373,137 @@ func (p *publisher) StartPeriod() error {
 	p.messenger.BroadcastStartPeriod(p.PeriodID, p.TargetSuperblockNumber)
 
 	p.SequenceNumber = 0
+	p.HighestDecidedSequenceNumber = 0
 	return nil
 }
 
 // ReceiveProof is called whenever a proof is received from a sequencer.
+// It returns an acknowledgment describing whether the proof was accepted or why it was ignored.
 func (p *publisher) ReceiveProof(
 	periodID compose.PeriodID,
 	superblockNumber compose.SuperblockNumber,
 	proof []byte,
 	chainID compose.ChainID,
-) {
+) ProofAck {
 	p.mu.Lock()
 
+	ack := p.checkProof(periodID, superblockNumber, chainID)
+	if ack == ProofAckAccepted && p.proofVerifier != nil {
+		// Verification may take a while and thus it is done outside locks.
+		p.mu.Unlock()
+		ack = p.verifyProof(superblockNumber, proof, chainID)
+		p.mu.Lock()
+		// State may have changed while verifying
+		if ack == ProofAckAccepted {
+			ack = p.checkProof(periodID, superblockNumber, chainID)
+		}
+	}
+	if ack != ProofAckAccepted {
+		// Proofs received after aggregation are checked too, while the stored ones are held (until publication)
+		equivocation := (ack == ProofAckIgnoredDuplicate || ack == ProofAckIgnoredAlreadyAggregated) &&
+			p.isEquivocation(superblockNumber, proof, chainID)
+		p.mu.Unlock()
+		if equivocation && p.onEquivocation != nil {
+			p.onEquivocation(chainID, superblockNumber)
+		}
+		return ack
+	}
+
+	if _, ok := p.Proofs[superblockNumber]; !ok {
+		p.Proofs[superblockNumber] = make(map[compose.ChainID][]byte)
+		p.FirstProofReceivedAt[superblockNumber] = p.now()
+	}
+	p.Proofs[superblockNumber][chainID] = proof
+	p.metrics.IncProofReceived(chainID)
+
+	// If didn't receive enough proofs, continue waiting.
+	if len(p.Proofs[superblockNumber]) < len(p.Chains) {
+		p.logger.Info().
+			Uint64("superblock_number", uint64(superblockNumber)).
+			Uint64("chain_id", uint64(chainID)).
+			Int("received_proofs", len(p.Proofs[superblockNumber])).
+			Int("total_chains", len(p.Chains)).
+			Msg("Received proof, waiting for more")
+		p.mu.Unlock()
+		return ProofAckAccepted
+	}
+
+	p.publishNextIfReady()
+	return ProofAckAccepted
+}
+
+// publishNextIfReady requests the network proof for the next superblock to be finalized and publishes it to L1,
+// if proofs from all chains have already been received for it.
+func (p *publisher) publishNextIfReady() {
+	// Caller must hold the p mutex, which is released before returning
+	superblockNumber := p.LastFinalizedSuperblockNumber + 1
+	proofs, ok := p.Proofs[superblockNumber]
+	_, aggregated := p.AggregatedSuperblocks[superblockNumber]
+	if !ok || aggregated || len(proofs) < len(p.Chains) {
+		p.mu.Unlock()
+		return
+	}
+
+	p.logger.Info().
+		Uint64("superblock_number", uint64(superblockNumber)).
+		Msg("Received enough proofs, generating proof")
+
+	seqProofs := make([][]byte, 0)
+	for _, seqProof := range proofs {
+		seqProofs = append(seqProofs, seqProof)
+	}
+	// Further proofs for this superblock don't change the outcome
+	p.AggregatedSuperblocks[superblockNumber] = struct{}{}
+
+	lastSuperblockHash := p.LastFinalizedSuperblockHash
+	p.mu.Unlock()
+
+	networkProof, err := p.prover.RequestSuperblockProof(superblockNumber, lastSuperblockHash, seqProofs)
+	if err != nil {
+		p.logger.Error().
+			Err(err).
+			Uint64("superblock_number", uint64(superblockNumber)).
+			Msg("Failed to generate network proof. Triggering rollback")
+		p.rollback()
+		return
+	}
+	p.metrics.IncNetworkProof()
+	p.mu.Lock()
+	waited := p.now().Sub(p.FirstProofReceivedAt[superblockNumber])
+	p.LastPublishedProofLatency = waited
+	delete(p.Proofs, superblockNumber)
+	delete(p.FirstProofReceivedAt, superblockNumber)
+	p.mu.Unlock()
+
+	if p.proofMetrics != nil {
+		p.proofMetrics.RecordPublication(superblockNumber, waited)
+	}
+	p.l1.PublishProof(superblockNumber, networkProof)
+	p.metrics.IncPublication()
+}
+
+// LastProofLatency returns the time from the first proof received to the L1 publication
+// of the last published superblock.
+func (p *publisher) LastProofLatency() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.LastPublishedProofLatency
+}
+
+// checkProof checks whether a proof can be accepted for the given superblock and chain.
+func (p *publisher) checkProof(
+	periodID compose.PeriodID,
+	superblockNumber compose.SuperblockNumber,
+	chainID compose.ChainID,
+) ProofAck {
+	// Caller must hold the p mutex
 	// If the proof is for an old superblock, ignore it.
 	if superblockNumber <= p.LastFinalizedSuperblockNumber {
 		p.logger.Warn().
 			Uint64("superblock_number", uint64(superblockNumber)).
 			Uint64("chain_id", uint64(chainID)).
 			Msg("Received proof for old superblock, ignoring")
-		p.mu.Unlock()
-		return
+		return ProofAckIgnoredOld
 	}
 
 	// If the proof is for an non-terminated superblock, ignore it.
@@ -196,8 +512,7 @@ func (p *publisher) ReceiveProof(
 			Uint64("superblock_number", uint64(superblockNumber)).
 			Uint64("chain_id", uint64(chainID)).
 			Msg("Received proof for non-terminated superblock, ignoring")
-		p.mu.Unlock()
-		return
+		return ProofAckIgnoredNotTerminated
 	}
 
 	// If the proof is for a superblock that is not the next one, ignore it.
@@ -206,102 +521,226 @@ func (p *publisher) ReceiveProof(
 			Uint64("superblock_number", uint64(superblockNumber)).
 			Uint64("chain_id", uint64(chainID)).
 			Msg("Received proof for superblock that is not the next one, ignoring")
-		p.mu.Unlock()
-		return
+		return ProofAckIgnoredNotNext
 	}
 
 	// Check period is correct
+	// superblockNumber < TargetSuperblockNumber at this point, while the period may be too low to match any
 	periodDiff := p.TargetSuperblockNumber - superblockNumber
-	expectedPeriod := p.PeriodID - compose.PeriodID(periodDiff)
-	if periodID != expectedPeriod {
+	expectedPeriod, ok := p.PeriodID.Sub(uint64(periodDiff))
+	if !ok || periodID != expectedPeriod {
 		p.logger.Warn().
 			Uint64("superblock_number", uint64(superblockNumber)).
 			Uint64("chain_id", uint64(chainID)).
 			Uint64("expected_period", uint64(expectedPeriod)).
 			Uint64("received_period", uint64(periodID)).
 			Msg("Received proof for wrong period, ignoring")
-		p.mu.Unlock()
-		return
+		return ProofAckIgnoredWrongPeriod
 	}
 
-	// If proof has already been received, ignore it.
-	if _, ok := p.Proofs[superblockNumber]; !ok {
-		p.Proofs[superblockNumber] = make(map[compose.ChainID][]byte)
+	// If proofs have already been aggregated, ignore it.
+	if _, ok := p.AggregatedSuperblocks[superblockNumber]; ok {
+		p.logger.Debug().
+			Uint64("superblock_number", uint64(superblockNumber)).
+			Uint64("chain_id", uint64(chainID)).
+			Msg("Received proof for already aggregated superblock, ignoring")
+		return ProofAckIgnoredAlreadyAggregated
 	}
+
+	// If proof has already been received, ignore it.
 	if _, ok := p.Proofs[superblockNumber][chainID]; ok {
 		p.logger.Warn().
 			Uint64("superblock_number", uint64(superblockNumber)).
 			Uint64("chain_id", uint64(chainID)).
 			Msg("Already received proof , ignoring")
-		p.mu.Unlock()
-		return
+		return ProofAckIgnoredDuplicate
 	}
 
-	p.Proofs[superblockNumber][chainID] = proof
+	return ProofAckAccepted
+}
 
-	// If didn't receive enough proofs, continue waiting.
-	if len(p.Proofs[superblockNumber]) < len(p.Chains) {
-		p.logger.Info().
-			Uint64("superblock_number", uint64(superblockNumber)).
-			Uint64("chain_id", uint64(chainID)).
-			Int("received_proofs", len(p.Proofs[superblockNumber])).
-			Int("total_chains", len(p.Chains)).
-			Msg("Received proof, waiting for more")
-		p.mu.Unlock()
-		return
+// isEquivocation returns whether the proof differs from the one already stored for the chain and superblock.
+func (p *publisher) isEquivocation(
+	superblockNumber compose.SuperblockNumber,
+	proof []byte,
+	chainID compose.ChainID,
+) bool {
+	// Caller must hold the p mutex
+	stored, ok := p.Proofs[superblockNumber][chainID]
+	if !ok || bytes.Equal(stored, proof) {
+		return false
 	}
-
-	p.logger.Info().
+	p.logger.Error().
 		Uint64("superblock_number", uint64(superblockNumber)).
 		Uint64("chain_id", uint64(chainID)).
-		Msg("Received enough proofs, generating proof")
-
-	seqProofs := make([][]byte, 0)
-	for _, seqProof := range p.Proofs[superblockNumber] {
-		seqProofs = append(seqProofs, seqProof)
-	}
-
-	lastSuperblockHash := p.LastFinalizedSuperblockHash
-	p.mu.Unlock()
+		Msg("Received a different proof from a chain that already sent one, keeping the first")
+	return true
+}
 
-	networkProof, err := p.prover.RequestSuperblockProof(superblockNumber, lastSuperblockHash, seqProofs)
-	if err != nil {
-		p.logger.Error().
+// verifyProof runs the configured proof verifier. Must be called without holding the p mutex.
+func (p *publisher) verifyProof(
+	superblockNumber compose.SuperblockNumber,
+	proof []byte,
+	chainID compose.ChainID,
+) ProofAck {
+	if err := p.proofVerifier(superblockNumber, chainID, proof); err != nil {
+		p.logger.Warn().
 			Err(err).
 			Uint64("superblock_number", uint64(superblockNumber)).
 			Uint64("chain_id", uint64(chainID)).
-			Msg("Failed to generate network proof. Triggering rollback")
-		p.rollback()
-		return
+			Msg("Received invalid proof, ignoring")
+		return ProofAckIgnoredInvalid
 	}
-	p.mu.Lock()
-	delete(p.Proofs, superblockNumber)
-	p.mu.Unlock()
-	p.l1.PublishProof(superblockNumber, networkProof)
+	return ProofAckAccepted
 }
 
 // StartInstance is called by the upper layer to try starting a new instance.
 // If the instance can not be started, it returns an error.
-// Else, it returns the created instance.
+// Else, it returns the created instance, which the upper layer is responsible for broadcasting.
 func (p *publisher) StartInstance(request compose.XTRequest) (compose.Instance, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Requests must have at least 2 transactions
-	if len(request.Transactions) < 2 {
-		return compose.Instance{}, ErrInvalidRequest
+	chains, err := p.checkStartInstance(request)
+	if err != nil {
+		return compose.Instance{}, err
+	}
+	return p.startInstance(request, chains), nil
+}
+
+// StartAndBroadcastInstance starts a new instance as StartInstance does, and broadcasts it to its participants
+// through the messenger. The broadcast happens outside the lock.
+// If it fails, the instance's chains are released (unless a rollback discarded the instance meanwhile)
+// and the broadcast error is returned.
+// Its sequence number isn't reused, as other instances may have been started meanwhile.
+func (p *publisher) StartAndBroadcastInstance(request compose.XTRequest) (compose.Instance, error) {
+	p.mu.Lock()
+	chains, err := p.checkStartInstance(request)
+	if err != nil {
+		p.mu.Unlock()
+		return compose.Instance{}, err
+	}
+	instance := p.startInstance(request, chains)
+	p.mu.Unlock()
+
+	if err := p.messenger.BroadcastStartInstance(instance); err != nil {
+		p.logger.Warn().
+			Err(err).
+			Str("instance_id", instance.ID.String()).
+			Msg("Failed to broadcast instance, releasing its chains")
+		p.mu.Lock()
+		// After a rollback, the chains may belong to another instance by now
+		if _, ok := p.ActiveInstances[instance.ID]; ok {
+			delete(p.ActiveInstances, instance.ID)
+			for _, chainID := range chains {
+				delete(p.ActiveChains, chainID)
+			}
+		}
+		p.mu.Unlock()
+		return compose.Instance{}, fmt.Errorf("broadcast instance %s: %w", instance.ID, err)
+	}
+	return instance, nil
+}
+
+// checkStartInstance returns the chains of the request if an instance can be started for it.
+func (p *publisher) checkStartInstance(request compose.XTRequest) ([]compose.ChainID, error) {
+	// Caller must hold the p mutex
+	if !validRequest(request) {
+		return nil, ErrInvalidRequest
 	}
 
 	chains := compose.ChainsFromRequest(request)
+	if err := p.checkKnownChains(chains); err != nil {
+		return nil, err
+	}
 	// Can't start instance if any participant is already active
 	if p.anyChainAlreadyActive(chains) {
-		return compose.Instance{}, ErrCannotStartInstance
+		return nil, ErrCannotStartInstance
 	}
+	return chains, nil
+}
 
+// checkKnownChains returns ErrUnknownChain if any chain is not in the configured chains,
+// since it would never send its proof for the superblock, or compose.ErrChainNotPermitted if the chain filter
+// doesn't permit it.
+func (p *publisher) checkKnownChains(chains []compose.ChainID) error {
+	// Caller must hold the p mutex
+	for _, chainID := range chains {
+		if _, ok := p.Chains[chainID]; !ok {
+			return fmt.Errorf("chain %d: %w", chainID, ErrUnknownChain)
+		}
+		if !p.chainFilter.IsPermitted(chainID) {
+			return fmt.Errorf("chain %d: %w", chainID, compose.ErrChainNotPermitted)
+		}
+	}
+	return nil
+}
+
+// QueueRequest adds the request to the end of the pending requests queue.
+// Queued requests are started by TryStartQueued.
+func (p *publisher) QueueRequest(request compose.XTRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !validRequest(request) {
+		return ErrInvalidRequest
+	}
+	if err := p.checkKnownChains(compose.ChainsFromRequest(request)); err != nil {
+		return err
+	}
+
+	p.RequestQueue = append(p.RequestQueue, request)
+	return nil
+}
+
+// TryStartQueued drains the queue in FIFO order, starting every request whose chains don't collide
+// with active instances (including the ones started in this same pass).
+// Requests that can't be started are kept in the queue, preserving their order.
+func (p *publisher) TryStartQueued() []compose.Instance {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	started := make([]compose.Instance, 0)
+	remaining := make([]compose.XTRequest, 0, len(p.RequestQueue))
+	for _, request := range p.RequestQueue {
+		chains := compose.ChainsFromRequest(request)
+		if p.anyChainAlreadyActive(chains) {
+			remaining = append(remaining, request)
+			continue
+		}
+		started = append(started, p.startInstance(request, chains))
+	}
+	p.RequestQueue = remaining
+
+	return started
+}
+
+// CancelQueued removes the first queued request with the given fingerprint (see RequestFingerprint),
+// so that it's never started. It returns whether such a request was queued.
+func (p *publisher) CancelQueued(fingerprint [32]byte) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, request := range p.RequestQueue {
+		if RequestFingerprint(request) != fingerprint {
+			continue
+		}
+		p.RequestQueue = slices.Delete(p.RequestQueue, i, i+1)
+		p.logger.Info().
+			Hex("fingerprint", fingerprint[:]).
+			Msg("Cancelled queued request")
+		return true
+	}
+	return false
+}
+
+// startInstance creates a new instance for the request and sets its chains as active.
+func (p *publisher) startInstance(request compose.XTRequest, chains []compose.ChainID) compose.Instance {
+	// Caller must hold the p mutex
 	// Create instance
 	p.SequenceNumber++
 	instance := compose.Instance{
-		ID: GenerateInstanceID(
+		ID: GenerateInstanceIDV2(
 			p.PeriodID,
 			p.SequenceNumber,
 			request,
@@ -314,6 +753,7 @@ func (p *publisher) StartInstance(request compose.XTRequest) (compose.Instance,
 	for _, chainID := range chains {
 		p.ActiveChains[chainID] = true
 	}
+	p.ActiveInstances[instance.ID] = struct{}{}
 
 	p.logger.Info().
 		Str("instance_id", instance.ID.String()).
@@ -322,7 +762,27 @@ func (p *publisher) StartInstance(request compose.XTRequest) (compose.Instance,
 		Any("chains", chains).
 		Msg("Starting new instance")
 
-	return instance, nil
+	if p.logRequestBytes {
+		p.logRequestTransactions(instance)
+	}
+
+	return instance
+}
+
+// logRequestTransactions logs, per chain, the number of transactions and their sizes in bytes.
+func (p *publisher) logRequestTransactions(instance compose.Instance) {
+	for _, txReq := range instance.XTRequest.Transactions {
+		sizes := make([]int, 0, len(txReq.Transactions))
+		for _, tx := range txReq.Transactions {
+			sizes = append(sizes, len(tx))
+		}
+		p.logger.Debug().
+			Str("instance_id", instance.ID.String()).
+			Uint64("chain_id", uint64(txReq.ChainID)).
+			Int("tx_count", len(txReq.Transactions)).
+			Ints("tx_sizes", sizes).
+			Msg("Instance request transactions")
+	}
 }
 
 // DecideInstance removes the instance from being active.
@@ -343,6 +803,12 @@ func (p *publisher) DecideInstance(instance compose.Instance) error {
 	for _, chainID := range chains {
 		delete(p.ActiveChains, chainID)
 	}
+	delete(p.ActiveInstances, instance.ID)
+
+	// Instances from previous periods don't count towards the current period's watermark
+	if instance.PeriodID == p.PeriodID && instance.SequenceNumber > p.HighestDecidedSequenceNumber {
+		p.HighestDecidedSequenceNumber = instance.SequenceNumber
+	}
 
 	p.logger.Info().
 		Str("instance_id", instance.ID.String()).
@@ -351,15 +817,25 @@ func (p *publisher) DecideInstance(instance compose.Instance) error {
 	return nil
 }
 
+// HighestDecidedSequence returns the highest sequence number decided in the current period (0 if none).
+// Compared with the started sequence numbers, it helps confirming that all started instances got decided.
+func (p *publisher) HighestDecidedSequence() compose.SequenceNumber {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.HighestDecidedSequenceNumber
+}
+
 // AdvanceSettledState is called when L1 emits a new settled state event.
+// If proofs from all chains were already buffered for the new next superblock, its network proof
+// is requested and published right away.
 func (p *publisher) AdvanceSettledState(
 	superblockNumber compose.SuperblockNumber,
 	superblockHash compose.SuperblockHash,
 ) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
 
 	if superblockNumber <= p.LastFinalizedSuperblockNumber {
+		p.mu.Unlock()
 		return ErrOldSettledState
 	}
 
@@ -369,35 +845,162 @@ func (p *publisher) AdvanceSettledState(
 
 	p.LastFinalizedSuperblockNumber = superblockNumber
 	p.LastFinalizedSuperblockHash = superblockHash
+	for aggregated := range p.AggregatedSuperblocks {
+		if aggregated <= superblockNumber {
+			delete(p.AggregatedSuperblocks, aggregated)
+		}
+	}
+	for pending := range p.Proofs {
+		if pending <= superblockNumber {
+			delete(p.Proofs, pending)
+			delete(p.FirstProofReceivedAt, pending)
+		}
+	}
+
+	p.publishNextIfReady()
 	return nil
 }
 
+// ShouldRollback reports whether the oldest pending superblock has exceeded the proof window,
+// i.e. whether the next StartPeriod would be rejected until a rollback happens.
+func (p *publisher) ShouldRollback() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.proofWindowExceeded(p.TargetSuperblockNumber + 1)
+}
+
+// proofWindowExceeded returns whether targeting the given superblock leaves the oldest pending superblock
+// (LastFinalizedSuperblockNumber + 1) outside the proof window.
+func (p *publisher) proofWindowExceeded(targetSuperblockNumber compose.SuperblockNumber) bool {
+	// Caller must hold the p mutex
+	if p.ProofWindow == 0 { // 0 means no constrain
+		return false
+	}
+	return targetSuperblockNumber > p.LastFinalizedSuperblockNumber+compose.SuperblockNumber(1+p.ProofWindow)
+}
+
 // ProofTimeout is called whenever a pending superblock is not proven within the allowed proof window.
 // It triggers a rollback to the last finalized superblock, resetting the active chains and sequence number.
-func (p *publisher) ProofTimeout() {
+// Only the oldest pending superblock (LastFinalizedSuperblockNumber + 1) can time out, since newer ones
+// are still within their proof window: timeouts for other superblocks return ErrNotOldestPending.
+func (p *publisher) ProofTimeout(superblockNumber compose.SuperblockNumber) error {
+	p.mu.Lock()
+	// The check and the reset are done under the same lock, so that a superblock finalized meanwhile
+	// (e.g. by AdvanceSettledState) can't get its successors' pipeline wiped.
+	finalized := p.LastFinalizedSuperblockNumber
+	if superblockNumber != finalized+1 {
+		p.mu.Unlock()
+		p.logger.Info().
+			Uint64("superblock_number", uint64(superblockNumber)).
+			Uint64("finalized_superblock_number", uint64(finalized)).
+			Msg("Ignoring proof timeout for a superblock other than the oldest pending one")
+		return fmt.Errorf("superblock %d, oldest pending is %d: %w", superblockNumber, finalized+1, ErrNotOldestPending)
+	}
+
 	p.logger.Info().
-		Uint64("finalized_superblock_number", uint64(p.LastFinalizedSuperblockNumber)).
+		Uint64("finalized_superblock_number", uint64(finalized)).
 		Msg("Proof timeout occurred, rolling back to last finalized superblock")
-	p.rollback()
+	record := p.rollbackLocked()
+	p.mu.Unlock()
+
+	p.broadcastRollback(record)
+	return nil
 }
 
 func (p *publisher) rollback() {
+	p.mu.Lock()
+	record := p.rollbackLocked()
+	p.mu.Unlock()
+
+	p.broadcastRollback(record)
+}
+
+// rollbackLocked resets the settlement pipeline to the last finalized superblock,
+// returning the rollback to be broadcast once the lock is released.
+func (p *publisher) rollbackLocked() RollbackRecord {
+	// Caller must hold the p mutex
+	p.resetSettlementPipeline()
+	p.LastRollback = &RollbackRecord{
+		PeriodID:         p.PeriodID,
+		SuperblockNumber: p.LastFinalizedSuperblockNumber,
+		SuperblockHash:   p.LastFinalizedSuperblockHash,
+	}
+	return *p.LastRollback
+}
+
+// broadcastRollback broadcasts a rollback and counts it. Must be called without holding the p mutex.
+func (p *publisher) broadcastRollback(record RollbackRecord) {
+	p.messenger.BroadcastRollback(record.PeriodID, record.SuperblockNumber, record.SuperblockHash)
+	p.metrics.IncRollback()
+}
+
+// ReceiveRollback handles a rollback broadcast received from the network.
+// In multi-publisher setups, a publisher may receive its own rollback back through gossip.
+// If the rollback matches the last one broadcast by this publisher, it's a no-op.
+// Otherwise, the rollback must target the last finalized superblock, and the local pipeline is reset
+// without re-broadcasting.
+func (p *publisher) ReceiveRollback(
+	periodID compose.PeriodID,
+	superblockNumber compose.SuperblockNumber,
+	superblockHash compose.SuperblockHash,
+) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	received := RollbackRecord{
+		PeriodID:         periodID,
+		SuperblockNumber: superblockNumber,
+		SuperblockHash:   superblockHash,
+	}
+	if p.LastRollback != nil && *p.LastRollback == received {
+		p.logger.Debug().
+			Uint64("period_id", uint64(periodID)).
+			Uint64("superblock_number", uint64(superblockNumber)).
+			Msg("Received own rollback, ignoring")
+		return nil
+	}
+
+	if superblockNumber != p.LastFinalizedSuperblockNumber || superblockHash != p.LastFinalizedSuperblockHash {
+		return ErrRollbackMismatch
+	}
+
+	p.logger.Info().
+		Uint64("period_id", uint64(periodID)).
+		Uint64("superblock_number", uint64(superblockNumber)).
+		Msg("Received rollback, resetting settlement pipeline")
+
+	p.resetSettlementPipeline()
+	p.LastRollback = &received
+	return nil
+}
+
+// resetSettlementPipeline discards active instances and pending proofs, resetting the target
+// superblock to the one right after the last finalized superblock.
+func (p *publisher) resetSettlementPipeline() {
+	// Caller must hold the p mutex
 	p.ActiveChains = make(map[compose.ChainID]bool)
+	p.ActiveInstances = make(map[compose.InstanceID]struct{})
 	p.SequenceNumber = 0
+	p.HighestDecidedSequenceNumber = 0
 	p.TargetSuperblockNumber = p.LastFinalizedSuperblockNumber + 1
-	p.messenger.BroadcastRollback(p.PeriodID, p.LastFinalizedSuperblockNumber, p.LastFinalizedSuperblockHash)
 
 	// Clear proofs
 	for superblockNumber := range p.Proofs {
 		delete(p.Proofs, superblockNumber)
 	}
+	for superblockNumber := range p.FirstProofReceivedAt {
+		delete(p.FirstProofReceivedAt, superblockNumber)
+	}
+	clear(p.AggregatedSuperblocks)
 }
 
 // Util functions
 
+// validRequest checks the request has at least 2 transactions.
+func validRequest(request compose.XTRequest) bool {
+	return len(request.Transactions) >= 2
+}
+
 func (p *publisher) anyChainAlreadyActive(chains []compose.ChainID) bool {
 	// Caller must hold the p mutex
 	// Check if any chain is already active
@@ -1,11 +1,15 @@
 package sbcp
 
 import (
+	"bytes"
 	"errors"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/compose-network/specs/compose"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -17,11 +21,12 @@ func newPublisherForTest(
 	hash compose.SuperblockHash,
 	window uint64,
 	chains map[compose.ChainID]struct{},
+	opts ...PublisherOption,
 ) (Publisher, *fakePublisherMessenger, *fakePublisherProver, *fakeL1) {
 	m := &fakePublisherMessenger{}
 	p := &fakePublisherProver{}
 	l1 := &fakeL1{}
-	pub, err := NewPublisher(p, m, l1, period, target, finalized, hash, window, testLogger(), chains)
+	pub, err := NewPublisher(p, m, l1, period, target, finalized, hash, window, testLogger(), chains, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -158,6 +163,7 @@ func TestPublisher_StartInstance_disjoint_sets_allowed(t *testing.T) {
 	inst1, err := pub.StartInstance(req1)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []compose.ChainID{1, 2}, inst1.Chains())
+	assert.Equal(t, GenerateInstanceIDV2(inst1.PeriodID, inst1.SequenceNumber, req1), inst1.ID)
 
 	// Disjoint {3,4} should be allowed
 	req2 := makeXTRequest(
@@ -277,6 +283,129 @@ func TestPublisher_StartInstance_populates_instance_fields(t *testing.T) {
 	assert.Empty(t, messenger.startInstances)
 }
 
+func TestPublisher_StartAndBroadcastInstance(t *testing.T) {
+	req := makeXTRequest(chainReq(1, []byte("x")), chainReq(2, []byte("y")))
+
+	t.Run("broadcasts", func(t *testing.T) {
+		pub, messenger, _, _ := newPublisherForTest(1, 0, 0, compose.SuperblockHash{}, 0, makeDefaultChainSet())
+
+		inst, err := pub.StartAndBroadcastInstance(req)
+		require.NoError(t, err)
+		assert.Equal(t, compose.SequenceNumber(1), inst.SequenceNumber)
+		assert.Equal(t, []compose.Instance{inst}, messenger.startInstances)
+
+		// Its chains are reserved
+		_, err = pub.StartAndBroadcastInstance(req)
+		require.ErrorIs(t, err, ErrCannotStartInstance)
+		assert.Len(t, messenger.startInstances, 1)
+	})
+
+	t.Run("failed_broadcast_releases_chains", func(t *testing.T) {
+		pub, messenger, _, _ := newPublisherForTest(1, 0, 0, compose.SuperblockHash{}, 0, makeDefaultChainSet())
+		broadcastErr := errors.New("transport down")
+		messenger.startInstanceErr = broadcastErr
+
+		_, err := pub.StartAndBroadcastInstance(req)
+		require.ErrorIs(t, err, broadcastErr)
+		assert.Empty(t, messenger.startInstances)
+		assert.Empty(t, pub.Snapshot().ActiveChains)
+
+		// The chains can be used again, but the sequence number isn't reused
+		messenger.startInstanceErr = nil
+		inst, err := pub.StartAndBroadcastInstance(req)
+		require.NoError(t, err)
+		assert.Equal(t, compose.SequenceNumber(2), inst.SequenceNumber)
+	})
+
+	t.Run("broadcasts_without_lock", func(t *testing.T) {
+		pub, messenger, _, _ := newPublisherForTest(1, 0, 0, compose.SuperblockHash{}, 0, makeDefaultChainSet())
+		other := makeXTRequest(chainReq(3, []byte("z")), chainReq(4, []byte("w")))
+		broadcastErr := errors.New("transport down")
+		messenger.startInstanceErr = broadcastErr
+
+		// Another instance is started while the first one is being broadcast
+		var started compose.Instance
+		messenger.onStartInstance = func() {
+			messenger.onStartInstance = nil
+			var err error
+			started, err = pub.StartInstance(other)
+			require.NoError(t, err)
+		}
+
+		_, err := pub.StartAndBroadcastInstance(req)
+		require.ErrorIs(t, err, broadcastErr)
+		assert.Equal(t, compose.SequenceNumber(2), started.SequenceNumber)
+		assert.Equal(t, []compose.ChainID{3, 4}, pub.Snapshot().ActiveChains)
+	})
+
+	t.Run("failed_broadcast_after_rollback_keeps_new_instance_chains", func(t *testing.T) {
+		pub, messenger, _, _ := newPublisherForTest(1, 0, 0, compose.SuperblockHash{}, 0, makeDefaultChainSet())
+		other := makeXTRequest(chainReq(1, []byte("z")), chainReq(2, []byte("w")))
+		broadcastErr := errors.New("transport down")
+		messenger.startInstanceErr = broadcastErr
+
+		// While broadcasting, a rollback discards the instance and its chains go to another one
+		var started compose.Instance
+		messenger.onStartInstance = func() {
+			messenger.onStartInstance = nil
+			require.NoError(t, pub.ReceiveRollback(compose.PeriodID(1), 0, compose.SuperblockHash{}))
+			var err error
+			started, err = pub.StartInstance(other)
+			require.NoError(t, err)
+		}
+
+		_, err := pub.StartAndBroadcastInstance(req)
+		require.ErrorIs(t, err, broadcastErr)
+		assert.Equal(t, []compose.ChainID{1, 2}, pub.Snapshot().ActiveChains)
+		require.NoError(t, pub.DecideInstance(started))
+		assert.Empty(t, pub.Snapshot().ActiveChains)
+	})
+}
+
+func TestPublisher_StartInstance_logs_request_transactions(t *testing.T) {
+	newLoggingPublisher := func(buf *bytes.Buffer, opts ...PublisherOption) Publisher {
+		pub, err := NewPublisher(
+			&fakePublisherProver{},
+			&fakePublisherMessenger{},
+			&fakeL1{},
+			compose.PeriodID(1),
+			compose.SuperblockNumber(1),
+			compose.SuperblockNumber(1),
+			compose.SuperblockHash{1},
+			0,
+			zerolog.New(buf).Level(zerolog.DebugLevel),
+			makeDefaultChainSet(),
+			opts...,
+		)
+		require.NoError(t, err)
+		return pub
+	}
+	req := makeXTRequest(
+		chainReq(1, []byte("ab"), []byte("cde")),
+		chainReq(2, []byte("f")),
+	)
+
+	t.Run("enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		pub := newLoggingPublisher(&buf, WithLogRequestBytes())
+		_, err := pub.StartInstance(req)
+		require.NoError(t, err)
+
+		out := buf.String()
+		assert.Contains(t, out, `"chain_id":1,"tx_count":2,"tx_sizes":[2,3]`)
+		assert.Contains(t, out, `"chain_id":2,"tx_count":1,"tx_sizes":[1]`)
+	})
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		var buf bytes.Buffer
+		pub := newLoggingPublisher(&buf)
+		_, err := pub.StartInstance(req)
+		require.NoError(t, err)
+
+		assert.NotContains(t, buf.String(), "tx_count")
+	})
+}
+
 func TestPublisher_DecideInstance_clears_active_and_validates_active(t *testing.T) {
 	pub, _, _, _ := newPublisherForTest(
 		compose.PeriodID(1),
@@ -312,6 +441,38 @@ func TestPublisher_DecideInstance_clears_active_and_validates_active(t *testing.
 	require.NoError(t, err)
 }
 
+func TestPublisher_HighestDecidedSequence(t *testing.T) {
+	pub, _, _, _ := newPublisherForTest(
+		compose.PeriodID(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockHash{1},
+		0,
+		makeDefaultChainSet(),
+	)
+	require.NoError(t, pub.StartPeriod())
+	assert.Equal(t, compose.SequenceNumber(0), pub.HighestDecidedSequence())
+
+	inst1, err := pub.StartInstance(makeXTRequest(chainReq(1, []byte("a")), chainReq(2, []byte("b"))))
+	require.NoError(t, err)
+	inst2, err := pub.StartInstance(makeXTRequest(chainReq(3, []byte("c")), chainReq(4, []byte("d"))))
+	require.NoError(t, err)
+	inst3, err := pub.StartInstance(makeXTRequest(chainReq(5, []byte("e")), chainReq(6, []byte("f"))))
+	require.NoError(t, err)
+
+	// Decided out of start order
+	require.NoError(t, pub.DecideInstance(inst2))
+	assert.Equal(t, compose.SequenceNumber(2), pub.HighestDecidedSequence())
+	require.NoError(t, pub.DecideInstance(inst1))
+	assert.Equal(t, compose.SequenceNumber(2), pub.HighestDecidedSequence())
+
+	// Instances from a previous period don't count in the new one
+	require.NoError(t, pub.StartPeriod())
+	assert.Equal(t, compose.SequenceNumber(0), pub.HighestDecidedSequence())
+	require.NoError(t, pub.DecideInstance(inst3))
+	assert.Equal(t, compose.SequenceNumber(0), pub.HighestDecidedSequence())
+}
+
 func TestPublisher_AdvanceSettledState_monotonic(t *testing.T) {
 	pub, _, _, _ := newPublisherForTest(
 		compose.PeriodID(1),
@@ -329,6 +490,50 @@ func TestPublisher_AdvanceSettledState_monotonic(t *testing.T) {
 	require.ErrorIs(t, err, ErrOldSettledState)
 }
 
+func TestPublisher_ShouldRollback_at_window_boundary(t *testing.T) {
+	finalized := compose.SuperblockNumber(5)
+
+	t.Run("window", func(t *testing.T) {
+		pub, _, _, _ := newPublisherForTest(
+			compose.PeriodID(3),
+			finalized,
+			finalized,
+			compose.SuperblockHash{7},
+			2,
+			makeDefaultChainSet(),
+		)
+		assert.False(t, pub.ShouldRollback())
+
+		// Target exactly ProofWindow superblocks past the finalized one
+		require.NoError(t, pub.StartPeriod())
+		require.NoError(t, pub.StartPeriod())
+		assert.False(t, pub.ShouldRollback())
+
+		// One past the window: the next StartPeriod is refused until a rollback happens
+		require.NoError(t, pub.StartPeriod())
+		assert.True(t, pub.ShouldRollback())
+		require.ErrorIs(t, pub.StartPeriod(), ErrCannotStartPeriod)
+
+		require.NoError(t, pub.ProofTimeout(finalized+1))
+		assert.False(t, pub.ShouldRollback())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		pub, _, _, _ := newPublisherForTest(
+			compose.PeriodID(3),
+			finalized,
+			finalized,
+			compose.SuperblockHash{7},
+			0,
+			makeDefaultChainSet(),
+		)
+		for range 10 {
+			require.NoError(t, pub.StartPeriod())
+			assert.False(t, pub.ShouldRollback())
+		}
+	})
+}
+
 func TestPublisher_ProofTimeout_rolls_back_and_resets_target(t *testing.T) {
 	finalized := compose.SuperblockNumber(5)
 	pub, m, _, _ := newPublisherForTest(
@@ -349,7 +554,7 @@ func TestPublisher_ProofTimeout_rolls_back_and_resets_target(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	pub.ProofTimeout()
+	require.NoError(t, pub.ProofTimeout(finalized+1))
 	// Expect a rollback broadcast to last finalized and target reset to F+1
 	require.Len(t, m.rollbacks, 1)
 	rb := m.rollbacks[0]
@@ -384,6 +589,54 @@ func TestPublisher_StartInstance_invalid_requests(t *testing.T) {
 	assert.Empty(t, m.startInstances)
 }
 
+func TestPublisher_StartInstance_rejects_unknown_chains(t *testing.T) {
+	pub, m, _, _ := newPublisherForTest(
+		compose.PeriodID(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockHash{1},
+		0,
+		makeDefaultChainSet(),
+	)
+	request := makeXTRequest(
+		chainReq(1, []byte("a")),
+		chainReq(11, []byte("b")),
+	)
+
+	_, err := pub.StartInstance(request)
+	require.ErrorIs(t, err, ErrUnknownChain)
+	require.ErrorIs(t, pub.QueueRequest(request), ErrUnknownChain)
+
+	// Nothing was reserved nor broadcast
+	assert.Empty(t, m.startInstances)
+	assert.Empty(t, pub.TryStartQueued())
+	_, err = pub.StartInstance(makeXTRequest(chainReq(1, []byte("a")), chainReq(2, []byte("b"))))
+	require.NoError(t, err)
+}
+
+func TestPublisher_StartInstance_rejects_filtered_chains(t *testing.T) {
+	pub, m, _, _ := newPublisherForTest(
+		compose.PeriodID(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockHash{1},
+		0,
+		makeDefaultChainSet(),
+		WithChainFilter(compose.NewChainFilter(nil, []compose.ChainID{2})),
+	)
+	request := makeXTRequest(chainReq(1, []byte("a")), chainReq(2, []byte("b")))
+
+	_, err := pub.StartInstance(request)
+	require.ErrorIs(t, err, compose.ErrChainNotPermitted)
+	_, err = pub.StartAndBroadcastInstance(request)
+	require.ErrorIs(t, err, compose.ErrChainNotPermitted)
+	require.ErrorIs(t, pub.QueueRequest(request), compose.ErrChainNotPermitted)
+	assert.Empty(t, m.startInstances)
+
+	_, err = pub.StartInstance(makeXTRequest(chainReq(1, []byte("a")), chainReq(3, []byte("c"))))
+	require.NoError(t, err)
+}
+
 func TestPublisher_ReceiveProof_aggregates_and_publishes(t *testing.T) {
 	chains := makeChainSet(compose.ChainID(1), compose.ChainID(2))
 	pub, _, prover, l1 := newPublisherForTest(
@@ -476,3 +729,509 @@ func TestPublisher_ReceiveProof_ignores_non_terminated_superblock(t *testing.T)
 	_, exists := impl.Proofs[superblock]
 	assert.False(t, exists)
 }
+
+func TestPublisher_ProofTimeout_only_for_oldest_pending(t *testing.T) {
+	finalized := compose.SuperblockNumber(5)
+	pub, m, _, _ := newPublisherForTest(
+		compose.PeriodID(3),
+		finalized,
+		finalized,
+		compose.SuperblockHash{7},
+		0,
+		makeDefaultChainSet(),
+	)
+	// Superblocks 6 and 7 in flight
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	// The newer superblock is still within its proof window
+	require.ErrorIs(t, pub.ProofTimeout(finalized+2), ErrNotOldestPending)
+	require.ErrorIs(t, pub.ProofTimeout(finalized), ErrNotOldestPending)
+	assert.Empty(t, m.rollbacks)
+	assert.Equal(t, finalized+2, pub.Snapshot().TargetSuperblockNumber)
+
+	require.NoError(t, pub.ProofTimeout(finalized+1))
+	require.Len(t, m.rollbacks, 1)
+	assert.Equal(t, finalized, m.rollbacks[0].SuperblockNumber)
+	assert.Equal(t, finalized+1, pub.Snapshot().TargetSuperblockNumber)
+}
+
+func TestPublisher_ProofTimeout_after_finalization(t *testing.T) {
+	finalized := compose.SuperblockNumber(5)
+	pub, m, _, _ := newPublisherForTest(
+		compose.PeriodID(3),
+		finalized,
+		finalized,
+		compose.SuperblockHash{7},
+		0,
+		makeDefaultChainSet(),
+	)
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	// The timed out superblock got finalized before the timeout was processed
+	require.NoError(t, pub.AdvanceSettledState(finalized+1, compose.SuperblockHash{8}))
+	require.ErrorIs(t, pub.ProofTimeout(finalized+1), ErrNotOldestPending)
+	assert.Empty(t, m.rollbacks)
+	assert.Equal(t, finalized+2, pub.Snapshot().TargetSuperblockNumber)
+
+	// The rollback is broadcast outside the publisher lock
+	var target compose.SuperblockNumber
+	m.onRollback = func() { target = pub.Snapshot().TargetSuperblockNumber }
+	require.NoError(t, pub.ProofTimeout(finalized+2))
+	require.Len(t, m.rollbacks, 1)
+	assert.Equal(t, finalized+1, m.rollbacks[0].SuperblockNumber)
+	assert.Equal(t, finalized+2, target)
+}
+
+func TestPublisher_ReceiveRollback_ignores_own_rollback(t *testing.T) {
+	finalized := compose.SuperblockNumber(5)
+	pub, m, _, _ := newPublisherForTest(
+		compose.PeriodID(3),
+		finalized,
+		finalized,
+		compose.SuperblockHash{7},
+		0,
+		makeDefaultChainSet(),
+	)
+
+	require.NoError(t, pub.ProofTimeout(finalized+1))
+	require.Len(t, m.rollbacks, 1)
+	rb := m.rollbacks[0]
+
+	// Activate chains after the rollback
+	_, err := pub.StartInstance(
+		makeXTRequest(
+			chainReq(1, []byte("a")),
+			chainReq(2, []byte("b")),
+		),
+	)
+	require.NoError(t, err)
+
+	// Own rollback looping back is a no-op
+	require.NoError(t, pub.ReceiveRollback(rb.PeriodID, rb.SuperblockNumber, rb.SuperblockHash))
+	assert.Len(t, m.rollbacks, 1, "must not re-broadcast")
+
+	impl, ok := pub.(*publisher)
+	require.True(t, ok)
+	assert.True(t, impl.ActiveChains[compose.ChainID(1)], "active chains must be kept")
+	assert.Equal(t, compose.SequenceNumber(1), impl.SequenceNumber)
+}
+
+func TestPublisher_ReceiveRollback_from_peer(t *testing.T) {
+	finalized := compose.SuperblockNumber(5)
+	pub, m, _, _ := newPublisherForTest(
+		compose.PeriodID(3),
+		finalized,
+		finalized,
+		compose.SuperblockHash{7},
+		0,
+		makeDefaultChainSet(),
+	)
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+	_, err := pub.StartInstance(
+		makeXTRequest(
+			chainReq(1, []byte("a")),
+			chainReq(2, []byte("b")),
+		),
+	)
+	require.NoError(t, err)
+
+	// Mismatched finalized state is rejected
+	err = pub.ReceiveRollback(compose.PeriodID(5), finalized, compose.SuperblockHash{8})
+	require.ErrorIs(t, err, ErrRollbackMismatch)
+
+	// Rollback from a peer resets the pipeline without re-broadcasting
+	require.NoError(t, pub.ReceiveRollback(compose.PeriodID(5), finalized, compose.SuperblockHash{7}))
+	assert.Empty(t, m.rollbacks)
+
+	impl, ok := pub.(*publisher)
+	require.True(t, ok)
+	assert.Empty(t, impl.ActiveChains)
+	assert.Equal(t, finalized+1, impl.TargetSuperblockNumber)
+}
+
+func TestPublisher_TryStartQueued_starts_maximal_disjoint_subset(t *testing.T) {
+	pub, _, _, _ := newPublisherForTest(
+		compose.PeriodID(4),
+		compose.SuperblockNumber(4),
+		compose.SuperblockNumber(4),
+		compose.SuperblockHash{1},
+		0,
+		makeDefaultChainSet(),
+	)
+
+	// Chains {1,2} are already active
+	_, err := pub.StartInstance(makeXTRequest(
+		chainReq(1, []byte("a")),
+		chainReq(2, []byte("b")),
+	))
+	require.NoError(t, err)
+
+	reqConflictActive := makeXTRequest(chainReq(2, []byte("c")), chainReq(3, []byte("d")))
+	reqDisjoint := makeXTRequest(chainReq(3, []byte("e")), chainReq(4, []byte("f")))
+	reqConflictBatch := makeXTRequest(chainReq(4, []byte("g")), chainReq(5, []byte("h")))
+	reqDisjoint2 := makeXTRequest(chainReq(6, []byte("i")), chainReq(7, []byte("j")))
+	for _, req := range []compose.XTRequest{reqConflictActive, reqDisjoint, reqConflictBatch, reqDisjoint2} {
+		require.NoError(t, pub.QueueRequest(req))
+	}
+
+	started := pub.TryStartQueued()
+	require.Len(t, started, 2)
+	assert.Equal(t, reqDisjoint, started[0].XTRequest)
+	assert.Equal(t, reqDisjoint2, started[1].XTRequest)
+	// Sequence numbers keep increasing across the batch
+	assert.Equal(t, compose.SequenceNumber(2), started[0].SequenceNumber)
+	assert.Equal(t, compose.SequenceNumber(3), started[1].SequenceNumber)
+
+	// Conflicting requests remain queued in order
+	impl, ok := pub.(*publisher)
+	require.True(t, ok)
+	assert.Equal(t, []compose.XTRequest{reqConflictActive, reqConflictBatch}, impl.RequestQueue)
+
+	// Nothing new can start until chains are released
+	assert.Empty(t, pub.TryStartQueued())
+
+	// Releasing {3,4} allows the {4,5} request, but {2,3} still conflicts with {1,2}
+	require.NoError(t, pub.DecideInstance(started[0]))
+	startedAfter := pub.TryStartQueued()
+	require.Len(t, startedAfter, 1)
+	assert.Equal(t, reqConflictBatch, startedAfter[0].XTRequest)
+	assert.Equal(t, compose.SequenceNumber(4), startedAfter[0].SequenceNumber)
+	assert.Equal(t, []compose.XTRequest{reqConflictActive}, impl.RequestQueue)
+}
+
+func TestPublisher_QueueRequest_rejects_invalid_request(t *testing.T) {
+	pub, _, _, _ := newPublisherForTest(
+		compose.PeriodID(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockHash{1},
+		0,
+		makeDefaultChainSet(),
+	)
+
+	require.ErrorIs(t, pub.QueueRequest(makeXTRequest(chainReq(1, []byte("only")))), ErrInvalidRequest)
+	assert.Empty(t, pub.TryStartQueued())
+}
+
+func TestPublisher_CancelQueued(t *testing.T) {
+	pub, _, _, _ := newPublisherForTest(
+		compose.PeriodID(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockNumber(1),
+		compose.SuperblockHash{1},
+		0,
+		makeDefaultChainSet(),
+	)
+	kept := makeXTRequest(chainReq(1, []byte("a")), chainReq(2, []byte("b")))
+	cancelled := makeXTRequest(chainReq(3, []byte("c")), chainReq(4, []byte("d")))
+	require.NoError(t, pub.QueueRequest(kept))
+	require.NoError(t, pub.QueueRequest(cancelled))
+
+	assert.True(t, pub.CancelQueued(RequestFingerprint(cancelled)))
+	assert.False(t, pub.CancelQueued(RequestFingerprint(cancelled)))
+
+	started := pub.TryStartQueued()
+	require.Len(t, started, 1)
+	assert.Equal(t, kept, started[0].XTRequest)
+}
+
+func TestPublisher_ReceiveProof_verifier_rejects_invalid_proof(t *testing.T) {
+	chains := makeChainSet(compose.ChainID(1), compose.ChainID(2))
+	verifier := func(_ compose.SuperblockNumber, chainID compose.ChainID, proof []byte) error {
+		if chainID == compose.ChainID(2) && string(proof) == "garbage" {
+			return errors.New("invalid proof")
+		}
+		return nil
+	}
+	pub, _, prover, l1 := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		chains,
+		WithProofVerifier(verifier),
+	)
+	prover.nextProof = []byte("network-proof")
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	ack := pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-1"), compose.ChainID(1))
+	assert.Equal(t, ProofAckAccepted, ack)
+
+	// Invalid proof is not counted, so aggregation waits
+	ack = pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("garbage"), compose.ChainID(2))
+	assert.Equal(t, ProofAckIgnoredInvalid, ack)
+	assert.Empty(t, prover.calls)
+	assert.Empty(t, l1.published)
+
+	impl, ok := pub.(*publisher)
+	require.True(t, ok)
+	assert.Len(t, impl.Proofs[compose.SuperblockNumber(6)], 1)
+
+	// A valid proof from the same chain completes the aggregation
+	ack = pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-2"), compose.ChainID(2))
+	assert.Equal(t, ProofAckAccepted, ack)
+	require.Len(t, prover.calls, 1)
+	assert.ElementsMatch(t, [][]byte{[]byte("proof-1"), []byte("proof-2")}, prover.calls[0].proofs)
+	require.Len(t, l1.published, 1)
+}
+
+func TestPublisher_ReceiveProof_acks(t *testing.T) {
+	chains := makeChainSet(compose.ChainID(1), compose.ChainID(2))
+	pub, _, _, _ := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		chains,
+	)
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	proof := []byte("proof")
+	assert.Equal(t, ProofAckIgnoredOld, pub.ReceiveProof(compose.PeriodID(10), 5, proof, 1))
+	assert.Equal(t, ProofAckIgnoredNotTerminated, pub.ReceiveProof(compose.PeriodID(13), 8, proof, 1))
+	assert.Equal(t, ProofAckIgnoredNotNext, pub.ReceiveProof(compose.PeriodID(12), 7, proof, 1))
+	assert.Equal(t, ProofAckIgnoredWrongPeriod, pub.ReceiveProof(compose.PeriodID(12), 6, proof, 1))
+	assert.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, proof, 1))
+	assert.Equal(t, ProofAckIgnoredDuplicate, pub.ReceiveProof(compose.PeriodID(11), 6, proof, 1))
+}
+
+func TestPublisher_ReceiveProof_period_underflow(t *testing.T) {
+	// Period 0 starts with target 5, so superblock 1 can't belong to any period
+	pub, _, prover, _ := newPublisherForTest(
+		compose.PeriodID(0),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(0),
+		compose.SuperblockHash{1},
+		0,
+		makeChainSet(compose.ChainID(1)),
+	)
+	require.NoError(t, pub.StartPeriod())
+
+	// 1 - (6 - 1) would wrap around to MaxUint64 - 3
+	wrapped := compose.PeriodID(math.MaxUint64 - 3)
+	assert.Equal(t, ProofAckIgnoredWrongPeriod, pub.ReceiveProof(wrapped, 1, []byte("proof"), 1))
+	assert.Empty(t, prover.calls)
+}
+
+func TestPublisher_ReceiveProof_already_aggregated(t *testing.T) {
+	pub, _, prover, l1 := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		makeChainSet(compose.ChainID(1), compose.ChainID(2)),
+	)
+	prover.nextProof = []byte("network-proof")
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("p1"), 1))
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("p2"), 2))
+	require.Len(t, prover.calls, 1)
+	require.Len(t, l1.published, 1)
+
+	// Until L1 finalizes superblock 6, resent proofs don't trigger a new aggregation
+	assert.Equal(t, ProofAckIgnoredAlreadyAggregated, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("p1"), 1))
+	assert.Equal(t, ProofAckIgnoredAlreadyAggregated, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("p2"), 2))
+	assert.Len(t, prover.calls, 1)
+	assert.Equal(t, "IgnoredAlreadyAggregated", ProofAckIgnoredAlreadyAggregated.String())
+
+	require.NoError(t, pub.AdvanceSettledState(6, compose.SuperblockHash{6}))
+	assert.Equal(t, ProofAckIgnoredOld, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("p1"), 1))
+	assert.Empty(t, pub.(*publisher).AggregatedSuperblocks)
+}
+
+func TestPublisher_AdvanceSettledState_publishes_buffered_proofs(t *testing.T) {
+	pub, _, prover, l1 := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		makeChainSet(compose.ChainID(1), compose.ChainID(2)),
+	)
+	prover.nextProof = []byte("network-proof-7")
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	// Superblock 7 is buffered (e.g. imported from a peer) while superblock 6 isn't finalized yet
+	imported := pub.ImportProofs(PublisherSnapshot{Proofs: map[compose.SuperblockNumber]map[compose.ChainID][]byte{
+		7: {1: []byte("p1"), 2: []byte("p2")},
+	}})
+	require.Equal(t, 2, imported)
+	assert.Empty(t, prover.calls)
+	assert.Empty(t, l1.published)
+
+	require.NoError(t, pub.AdvanceSettledState(6, compose.SuperblockHash{6}))
+
+	require.Len(t, prover.calls, 1)
+	assert.Equal(t, compose.SuperblockNumber(7), prover.calls[0].superblock)
+	assert.Equal(t, compose.SuperblockHash{6}, prover.calls[0].hash)
+	assert.ElementsMatch(t, [][]byte{[]byte("p1"), []byte("p2")}, prover.calls[0].proofs)
+	require.Len(t, l1.published, 1)
+	assert.Equal(t, compose.SuperblockNumber(7), l1.published[0].superblock)
+	assert.Equal(t, []byte("network-proof-7"), l1.published[0].proof)
+	assert.NotContains(t, pub.(*publisher).Proofs, compose.SuperblockNumber(7))
+}
+
+func TestPublisher_ReceiveProof_equivocation_hook(t *testing.T) {
+	chains := makeChainSet(compose.ChainID(1), compose.ChainID(2))
+	type equivocation struct {
+		chainID    compose.ChainID
+		superblock compose.SuperblockNumber
+	}
+	var fired []equivocation
+	pub, _, prover, _ := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		chains,
+		WithOnEquivocation(func(chainID compose.ChainID, superblock compose.SuperblockNumber) {
+			fired = append(fired, equivocation{chainID: chainID, superblock: superblock})
+		}),
+	)
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-a"), 1))
+
+	// Resending the same proof is a plain duplicate
+	assert.Equal(t, ProofAckIgnoredDuplicate, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-a"), 1))
+	assert.Empty(t, fired)
+
+	// A different proof fires the hook and doesn't overwrite the stored one
+	assert.Equal(t, ProofAckIgnoredDuplicate, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-b"), 1))
+	assert.Equal(t, []equivocation{{chainID: 1, superblock: 6}}, fired)
+
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-c"), 2))
+	require.Len(t, prover.calls, 1)
+	assert.ElementsMatch(t, [][]byte{[]byte("proof-a"), []byte("proof-c")}, prover.calls[0].proofs)
+}
+
+func TestPublisher_ReceiveProof_equivocation_after_aggregation(t *testing.T) {
+	var fired []compose.ChainID
+	pub, _, prover, l1 := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		makeChainSet(compose.ChainID(1), compose.ChainID(2)),
+		WithOnEquivocation(func(chainID compose.ChainID, _ compose.SuperblockNumber) {
+			fired = append(fired, chainID)
+		}),
+	)
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+
+	// While the network proof is being generated, chain 1 sends a conflicting proof, and chain 2 resends its own
+	var acks []ProofAck
+	prover.onRequest = func() {
+		acks = append(acks,
+			pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-a2"), 1),
+			pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-b"), 2),
+		)
+	}
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-a"), 1))
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-b"), 2))
+
+	assert.Equal(t, []ProofAck{ProofAckIgnoredAlreadyAggregated, ProofAckIgnoredAlreadyAggregated}, acks)
+	assert.Equal(t, []compose.ChainID{1}, fired)
+	require.Len(t, l1.published, 1)
+}
+
+func TestPublisher_ReceiveProof_records_publication_latency(t *testing.T) {
+	chains := makeChainSet(compose.ChainID(1), compose.ChainID(2))
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	metrics := &fakeProofMetrics{}
+	pub, _, prover, l1 := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		chains,
+		WithClock(clock.Now),
+		WithProofMetrics(metrics),
+	)
+	prover.nextProof = []byte("network-proof")
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+	assert.Zero(t, pub.LastProofLatency())
+
+	pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-1"), compose.ChainID(1))
+	clock.Advance(90 * time.Second)
+	pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-2"), compose.ChainID(2))
+
+	require.Len(t, l1.published, 1)
+	require.Len(t, metrics.publications, 1)
+	assert.Equal(t, compose.SuperblockNumber(6), metrics.publications[0].superblock)
+	assert.Equal(t, 90*time.Second, metrics.publications[0].waited)
+	assert.Equal(t, 90*time.Second, pub.LastProofLatency())
+}
+
+func TestPublisher_Metrics(t *testing.T) {
+	chains := makeChainSet(compose.ChainID(1), compose.ChainID(2))
+	newPublisher := func() (Publisher, *fakePublisherProver, *countingPublisherMetrics) {
+		metrics := &countingPublisherMetrics{}
+		pub, _, prover, _ := newPublisherForTest(
+			compose.PeriodID(10),
+			compose.SuperblockNumber(5),
+			compose.SuperblockNumber(5),
+			compose.SuperblockHash{1},
+			0,
+			chains,
+			WithPublisherMetrics(metrics),
+		)
+		require.NoError(t, pub.StartPeriod())
+		require.NoError(t, pub.StartPeriod())
+		return pub, prover, metrics
+	}
+
+	t.Run("publication", func(t *testing.T) {
+		pub, prover, metrics := newPublisher()
+		prover.nextProof = []byte("network-proof")
+
+		pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-1"), compose.ChainID(1))
+		// Ignored proofs aren't counted
+		pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-1"), compose.ChainID(1))
+		assert.Equal(t, map[compose.ChainID]int{1: 1}, metrics.proofsReceived)
+		assert.Zero(t, metrics.networkProofs)
+
+		pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-2"), compose.ChainID(2))
+		assert.Equal(t, map[compose.ChainID]int{1: 1, 2: 1}, metrics.proofsReceived)
+		assert.Equal(t, 1, metrics.networkProofs)
+		assert.Equal(t, 1, metrics.publications)
+		assert.Zero(t, metrics.rollbacks)
+	})
+
+	t.Run("network_proof_failure", func(t *testing.T) {
+		pub, prover, metrics := newPublisher()
+		prover.err = errors.New("boom")
+
+		pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-1"), compose.ChainID(1))
+		pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), []byte("proof-2"), compose.ChainID(2))
+		assert.Zero(t, metrics.networkProofs)
+		assert.Zero(t, metrics.publications)
+		assert.Equal(t, 1, metrics.rollbacks)
+	})
+
+	t.Run("proof_timeout", func(t *testing.T) {
+		pub, _, metrics := newPublisher()
+
+		require.NoError(t, pub.ProofTimeout(compose.SuperblockNumber(6)))
+		assert.Equal(t, 1, metrics.rollbacks)
+	})
+}
@@ -8,14 +8,50 @@ import (
 	"github.com/compose-network/specs/compose"
 )
 
+// InstanceIDDomain is the domain-separation tag prepended by GenerateInstanceIDV2,
+// so that instance IDs can't collide with other SHA256 preimages in the system.
+const InstanceIDDomain = "compose-instance-v1"
+
+// RequestFingerprintDomain is the domain-separation tag prepended by RequestFingerprint.
+const RequestFingerprintDomain = "compose-request-v1"
+
 // GenerateInstanceID returns SHA256(periodID || seq || tx1 || tx2 || ... || txn).
+// It's kept for compatibility with already generated IDs, GenerateInstanceIDV2 is the default.
 func GenerateInstanceID(
 	periodID compose.PeriodID,
 	seq compose.SequenceNumber,
 	xtRequest compose.XTRequest,
 ) compose.InstanceID {
-	var b [8]byte
 	buf := bytes.NewBuffer(nil)
+	writeInstanceIDPreimage(buf, periodID, seq, xtRequest)
+
+	sum := sha256.Sum256(buf.Bytes())
+	return sum
+}
+
+// GenerateInstanceIDV2 returns SHA256(InstanceIDDomain || periodID || seq || tx1 || tx2 || ... || txn).
+// It's the default instance ID used by the publisher.
+func GenerateInstanceIDV2(
+	periodID compose.PeriodID,
+	seq compose.SequenceNumber,
+	xtRequest compose.XTRequest,
+) compose.InstanceID {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString(InstanceIDDomain)
+	writeInstanceIDPreimage(buf, periodID, seq, xtRequest)
+
+	sum := sha256.Sum256(buf.Bytes())
+	return sum
+}
+
+// writeInstanceIDPreimage writes periodID || seq || tx1 || tx2 || ... || txn into buf.
+func writeInstanceIDPreimage(
+	buf *bytes.Buffer,
+	periodID compose.PeriodID,
+	seq compose.SequenceNumber,
+	xtRequest compose.XTRequest,
+) {
+	var b [8]byte
 
 	// Encode period timestamp (nanoseconds) as 8 bytes big-endian
 	binary.BigEndian.PutUint64(b[:], uint64(periodID))
@@ -25,6 +61,23 @@ func GenerateInstanceID(
 	binary.BigEndian.PutUint64(b[:], uint64(seq))
 	buf.Write(b[:])
 
+	writeRequest(buf, xtRequest)
+}
+
+// RequestFingerprint returns SHA256(RequestFingerprintDomain || tx1 || tx2 || ... || txn),
+// identifying a request before it gets an instance ID (e.g. while queued).
+func RequestFingerprint(xtRequest compose.XTRequest) [32]byte {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString(RequestFingerprintDomain)
+	writeRequest(buf, xtRequest)
+
+	return sha256.Sum256(buf.Bytes())
+}
+
+// writeRequest writes tx1 || tx2 || ... || txn into buf.
+func writeRequest(buf *bytes.Buffer, xtRequest compose.XTRequest) {
+	var b [8]byte
+
 	// Append each transaction's chain ID, length and raw bytes
 	for _, req := range xtRequest.Transactions {
 		// Chain identifier
@@ -46,7 +99,10 @@ func GenerateInstanceID(
 			}
 		}
 	}
+}
 
-	sum := sha256.Sum256(buf.Bytes())
-	return sum
+// VerifyInstanceID reports whether the instance ID matches the one computed by GenerateInstanceIDV2
+// from its period, sequence number and request, detecting instances forwarded with a tampered ID.
+func VerifyInstanceID(instance compose.Instance) bool {
+	return instance.ID == GenerateInstanceIDV2(instance.PeriodID, instance.SequenceNumber, instance.XTRequest)
 }
@@ -1,8 +1,11 @@
 package sbcp
 
 import (
+	"encoding/hex"
 	"testing"
 
+	"github.com/compose-network/specs/compose"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,3 +58,59 @@ func TestGenerateInstanceID_stability_and_sensitivity(t *testing.T) {
 	idG := GenerateInstanceID(10, 3, reqOmit)
 	assert.NotEqual(t, idF, idG, "empty tx bytes are not ignored in ID")
 }
+
+func TestGenerateInstanceID_golden(t *testing.T) {
+	req := makeXTRequest(
+		chainReq(1, []byte{0x01, 0x02}),
+		chainReq(2, []byte{0x03}),
+	)
+
+	// Legacy IDs must not change
+	v1 := GenerateInstanceID(10, 1, req)
+	assert.Equal(t, "705c749f730e256b7d4a7da576cd2dff4add8e53195b121ab88829b45317bd43", hex.EncodeToString(v1[:]))
+
+	v2 := GenerateInstanceIDV2(10, 1, req)
+	assert.Equal(t, "65c708f37fc65d379353146d523584dc60533562fbe076cc21ec2a6912d436cc", hex.EncodeToString(v2[:]))
+	assert.NotEqual(t, v1, v2)
+}
+
+func TestVerifyInstanceID(t *testing.T) {
+	req := makeXTRequest(
+		chainReq(1, []byte{0x01, 0x02}),
+		chainReq(2, []byte{0x03}),
+	)
+	instance := compose.Instance{
+		ID:             GenerateInstanceIDV2(10, 1, req),
+		PeriodID:       10,
+		SequenceNumber: 1,
+		XTRequest:      req,
+	}
+	assert.True(t, VerifyInstanceID(instance))
+
+	tampered := instance
+	tampered.ID[0] ^= 0xFF
+	assert.False(t, VerifyInstanceID(tampered))
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	req := makeXTRequest(
+		chainReq(1, []byte{0x01, 0x02}),
+		chainReq(2, []byte{0x03}),
+	)
+	same := makeXTRequest(
+		chainReq(1, []byte{0x01, 0x02}),
+		chainReq(2, []byte{0x03}),
+	)
+	assert.Equal(t, RequestFingerprint(req), RequestFingerprint(same))
+
+	other := makeXTRequest(
+		chainReq(1, []byte{0x01, 0x02}),
+		chainReq(2, []byte{0x04}),
+	)
+	assert.NotEqual(t, RequestFingerprint(req), RequestFingerprint(other))
+
+	// Not the same preimage as instance IDs
+	id := GenerateInstanceIDV2(0, 0, req)
+	fingerprint := RequestFingerprint(req)
+	assert.NotEqual(t, id[:], fingerprint[:])
+}
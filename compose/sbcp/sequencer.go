@@ -3,6 +3,8 @@ package sbcp
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/rs/zerolog"
@@ -21,8 +23,19 @@ var (
 	ErrNoActiveInstance         = errors.New("no active instance")
 	ErrActiveInstanceMismatch   = errors.New("mismatched active instance ID")
 	ErrMismatchedFinalizedState = errors.New("mismatched finalized state")
-	ErrPeriodIDMismatch         = errors.New("instance period ID does not match current block period ID")
 	ErrLowSequencerNumber       = errors.New("instance sequence number is not greater than last sequence number")
+	ErrSealedChainNotContiguous = errors.New("sealed block chain is not contiguous")
+	ErrSettlementSuperseded     = errors.New("settlement superseded by a rollback")
+	ErrInvalidBlockHeader       = errors.New("invalid block header")
+
+	ErrPeriodIDMismatch = compose.NewError(
+		compose.ErrPeriodMismatch,
+		"instance period ID does not match current block period ID",
+	)
+	ErrFuturePeriod = compose.NewError(
+		compose.ErrPeriodMismatch,
+		"instance period ID is ahead of the current period ID",
+	)
 )
 
 type Sequencer interface {
@@ -38,7 +51,8 @@ type Sequencer interface {
 	ReceiveXTRequest(ctx context.Context, request compose.XTRequest) error
 
 	// AdvanceSettledState is called when the L1 settlement event has occurred.
-	AdvanceSettledState(SettledState)
+	// Stale (non-advancing) settled states are ignored, returning ErrOldSettledState.
+	AdvanceSettledState(SettledState) error
 
 	// Block builder policy
 	// BeginBlock is called at start of a new block
@@ -49,8 +63,43 @@ type Sequencer interface {
 	OnStartInstance(id compose.InstanceID, periodID compose.PeriodID, sequenceNumber compose.SequenceNumber) error
 	// OnDecidedInstance is an SCP decision hook. Unlocks local txs (internal logic).
 	OnDecidedInstance(id compose.InstanceID) error
+	// ActiveInstance returns the ID of the active instance, if any.
+	ActiveInstance() (compose.InstanceID, bool)
 	// EndBlock: hook for when block ends
 	EndBlock(ctx context.Context, b BlockHeader) error
+
+	// ValidateSealedChain checks the consistency of the sealed blocks across periods (e.g. after a rollback).
+	ValidateSealedChain() error
+
+	// SettlementStatus returns the current state of the settlement pipeline.
+	SettlementStatus() SettlementStatus
+
+	// PeriodHistory returns the most recent periods, oldest first, with their last sealed block.
+	PeriodHistory() []PeriodRecord
+}
+
+// DefaultPeriodHistorySize is the number of periods kept by PeriodHistory unless WithPeriodHistorySize is used.
+const DefaultPeriodHistorySize = 64
+
+// PeriodRecord describes a period started by the sequencer.
+type PeriodRecord struct {
+	PeriodID               compose.PeriodID
+	TargetSuperblockNumber compose.SuperblockNumber
+	// SealedBlock is the last block sealed for the period (nil if none yet).
+	SealedBlock *SealedBlockHeader
+}
+
+// SettlementStatus describes where the settlement pipeline currently is.
+type SettlementStatus struct {
+	PeriodID               compose.PeriodID
+	TargetSuperblockNumber compose.SuperblockNumber
+	// WaitingForSeal is set when a block from a previous period is still open,
+	// so that period's settlement waits until the block is sealed.
+	WaitingForSeal bool
+	// InFlight is set while a proof is being requested to the prover.
+	InFlight bool
+	// LastProofSentPeriodID is the last period for which a proof was sent to the SP (nil if none).
+	LastProofSentPeriodID *compose.PeriodID
 }
 
 type SequencerProver interface {
@@ -77,8 +126,9 @@ type SequencerState struct {
 	PeriodID               compose.PeriodID
 	TargetSuperblockNumber compose.SuperblockNumber // from StartPeriod.target_superblock_number
 
-	// PendingBlock represents the block being built.
-	PendingBlock       *PendingBlock
+	// PendingBlocks holds the blocks being built, at most one per period.
+	// At a period boundary, the previous period's block may still be open while the next one begins.
+	PendingBlocks      map[compose.PeriodID]PendingBlock
 	ActiveInstanceID   *compose.InstanceID     // nil if no active instance
 	LastSequenceNumber *compose.SequenceNumber // nil if no started instance in this period
 
@@ -86,15 +136,70 @@ type SequencerState struct {
 	Head BlockNumber
 
 	SealedBlockHead map[compose.PeriodID]SealedBlockHeader
-	SettledState    SettledState
+	// SealedBlockFirst holds the number of the first block sealed in each period, so that gaps between
+	// periods can be detected even when periods have several blocks.
+	SealedBlockFirst map[compose.PeriodID]BlockNumber
+	SettledState     SettledState
+	// Superblocks whose proofs are being requested to the prover. Rollbacks discard them.
+	SettlingSuperblocks map[compose.SuperblockNumber]struct{}
+	// Last period for which a proof was sent to the SP (nil if none)
+	LastProofSentPeriodID *compose.PeriodID
+	// Recent periods, oldest first, bounded by periodHistorySize
+	History []PeriodRecord
 
 	logger zerolog.Logger
 }
 
+// SequencerOption configures optional behavior of the sequencer.
+type SequencerOption func(*sequencer)
+
+// WithOnLocalTxUnlocked sets a callback fired whenever local tx inclusion gets unlocked,
+// i.e. when the active instance is decided or discarded by a rollback.
+// It lets the block builder react immediately instead of polling CanIncludeLocalTx.
+// The callback runs outside the sequencer lock.
+func WithOnLocalTxUnlocked(callback func()) SequencerOption {
+	return func(s *sequencer) {
+		s.onLocalTxUnlocked = callback
+	}
+}
+
+// WithOnProofSent sets a callback fired after each settlement proof is successfully sent to the SP,
+// e.g. to start a proof acknowledgment timer. It isn't fired if the proof couldn't be generated or sent.
+// The callback runs outside the sequencer lock.
+func WithOnProofSent(
+	callback func(periodID compose.PeriodID, superblockNumber compose.SuperblockNumber),
+) SequencerOption {
+	return func(s *sequencer) {
+		s.onProofSent = callback
+	}
+}
+
+// WithPeriodHistorySize sets how many periods are kept by PeriodHistory (DefaultPeriodHistorySize by default).
+func WithPeriodHistorySize(size int) SequencerOption {
+	return func(s *sequencer) {
+		s.periodHistorySize = size
+	}
+}
+
+// WithHeaderValidation makes EndBlock reject headers with a zero block hash or state root.
+// Disabled by default, so that minimal headers (e.g. carrying only the block number) are accepted.
+func WithHeaderValidation() SequencerOption {
+	return func(s *sequencer) {
+		s.validateHeaders = true
+	}
+}
+
 type sequencer struct {
-	mu        sync.Mutex
-	prover    SequencerProver
-	messenger SequencerMessenger
+	mu                sync.Mutex
+	prover            SequencerProver
+	messenger         SequencerMessenger
+	onLocalTxUnlocked func() // optional
+	// Optional callback fired after each settlement proof is sent
+	onProofSent func(periodID compose.PeriodID, superblockNumber compose.SuperblockNumber)
+	// Whether to validate sealed block headers on EndBlock
+	validateHeaders bool
+	// Maximum number of periods kept in History
+	periodHistorySize int
 	SequencerState
 }
 
@@ -105,28 +210,41 @@ func NewSequencer(
 	targetSuperblock compose.SuperblockNumber,
 	settledState SettledState,
 	logger zerolog.Logger,
+	opts ...SequencerOption,
 ) Sequencer {
-	return &sequencer{
-		mu:        sync.Mutex{},
-		prover:    prover,
-		messenger: messenger,
+	s := &sequencer{
+		mu:                sync.Mutex{},
+		prover:            prover,
+		messenger:         messenger,
+		periodHistorySize: DefaultPeriodHistorySize,
 		SequencerState: SequencerState{
 			PeriodID:               periodID,
 			TargetSuperblockNumber: targetSuperblock,
-			PendingBlock:           nil,
+			PendingBlocks:          make(map[compose.PeriodID]PendingBlock),
 			ActiveInstanceID:       nil,
 			LastSequenceNumber:     nil,
 			Head:                   settledState.BlockHeader.Number,
 			SealedBlockHead:        make(map[compose.PeriodID]SealedBlockHeader),
+			SealedBlockFirst:       make(map[compose.PeriodID]BlockNumber),
 			SettledState:           settledState,
+			SettlingSuperblocks:    make(map[compose.SuperblockNumber]struct{}),
 			logger:                 logger,
 		},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.recordPeriod(periodID, targetSuperblock)
+	return s
 }
 
 // ReceiveXTRequest is called whenever a request from a user is received.
 // It should be forwarded to the publisher, who has the rights of starting an instance for it.
+// Malformed requests (see compose.XTRequest.Validate) are rejected locally with ErrInvalidRequest.
 func (s *sequencer) ReceiveXTRequest(ctx context.Context, request compose.XTRequest) error {
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
 	return s.messenger.ForwardRequest(ctx, request)
 }
 
@@ -146,7 +264,8 @@ func (s *sequencer) StartPeriod(
 	s.PeriodID = periodID
 	s.TargetSuperblockNumber = targetSuperblockNumber
 	s.LastSequenceNumber = nil
-	noPendingBlock := s.PendingBlock == nil
+	s.recordPeriod(periodID, targetSuperblockNumber)
+	noPendingBlock := len(s.PendingBlocks) == 0
 
 	s.mu.Unlock()
 
@@ -154,16 +273,35 @@ func (s *sequencer) StartPeriod(
 	// Else, it can be triggered right away.
 	if noPendingBlock {
 		s.logger.Info().Msg("No pending block, triggering settlement pipeline")
-		return s.startSettlement(ctx, periodID-1, targetSuperblockNumber-1)
+		return s.settlePreviousPeriod(ctx, periodID, targetSuperblockNumber)
 	}
 
 	s.logger.Info().Msg("Started new period, but pending block exists, settlement pipeline will wait")
 	return nil
 }
 
+// settlePreviousPeriod starts the settlement pipeline for the period before the given one.
+// In the genesis period (period or target superblock 0) there's no previous period, so it's skipped.
+func (s *sequencer) settlePreviousPeriod(
+	ctx context.Context,
+	periodID compose.PeriodID,
+	targetSuperblockNumber compose.SuperblockNumber,
+) error {
+	settlementPeriod, okPeriod := periodID.Sub(1)
+	settlementSuperblock, okSuperblock := targetSuperblockNumber.Sub(1)
+	if !okPeriod || !okSuperblock {
+		s.logger.Info().
+			Uint64("period_id", uint64(periodID)).
+			Uint64("target_superblock_number", uint64(targetSuperblockNumber)).
+			Msg("No previous period to settle, skipping settlement pipeline")
+		return nil
+	}
+	return s.startSettlement(ctx, settlementPeriod, settlementSuperblock)
+}
+
 // startSettlement starts the settlement pipeline for the given period.
 // It requests a proof from the prover. Note that this operation may take a while and thus it is done outside locks.
-// Then, it sends the proof to the SP.
+// Then, it sends the proof to the SP, unless a rollback invalidated the superblock in the meantime.
 func (s *sequencer) startSettlement(
 	ctx context.Context,
 	periodID compose.PeriodID,
@@ -175,15 +313,112 @@ func (s *sequencer) startSettlement(
 	if ok {
 		header = &block.BlockHeader
 	}
+	s.SettlingSuperblocks[superblockNumber] = struct{}{}
 	s.mu.Unlock()
 	// Request proof to prover
 	proof, err := s.prover.RequestProofs(ctx, header, superblockNumber)
+
+	s.mu.Lock()
+	_, stillSettling := s.SettlingSuperblocks[superblockNumber]
+	delete(s.SettlingSuperblocks, superblockNumber)
+	s.mu.Unlock()
+
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to request proofs from prover")
 		return err
 	}
+	if !stillSettling {
+		s.logger.Warn().
+			Uint64("period_id", uint64(periodID)).
+			Uint64("superblock_number", uint64(superblockNumber)).
+			Msg("Superblock was rolled back while proving, dropping stale proof")
+		return ErrSettlementSuperseded
+	}
 	// Send proof to SP
-	return s.messenger.SendProof(ctx, periodID, superblockNumber, proof)
+	if err := s.messenger.SendProof(ctx, periodID, superblockNumber, proof); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.LastProofSentPeriodID == nil || periodID > *s.LastProofSentPeriodID {
+		s.LastProofSentPeriodID = &periodID
+	}
+	s.mu.Unlock()
+
+	if s.onProofSent != nil {
+		s.onProofSent(periodID, superblockNumber)
+	}
+	return nil
+}
+
+// SettlementStatus returns the current state of the settlement pipeline.
+func (s *sequencer) SettlementStatus() SettlementStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	waitingForSeal := false
+	for periodID := range s.PendingBlocks {
+		if periodID < s.PeriodID {
+			waitingForSeal = true
+			break
+		}
+	}
+
+	var lastProofSent *compose.PeriodID
+	if s.LastProofSentPeriodID != nil {
+		periodID := *s.LastProofSentPeriodID
+		lastProofSent = &periodID
+	}
+
+	return SettlementStatus{
+		PeriodID:               s.PeriodID,
+		TargetSuperblockNumber: s.TargetSuperblockNumber,
+		WaitingForSeal:         waitingForSeal,
+		InFlight:               len(s.SettlingSuperblocks) > 0,
+		LastProofSentPeriodID:  lastProofSent,
+	}
+}
+
+// PeriodHistory returns the most recent periods, oldest first, with their last sealed block.
+func (s *sequencer) PeriodHistory() []PeriodRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := make([]PeriodRecord, 0, len(s.History))
+	for _, record := range s.History {
+		if record.SealedBlock != nil {
+			sealed := *record.SealedBlock
+			record.SealedBlock = &sealed
+		}
+		history = append(history, record)
+	}
+	return history
+}
+
+// recordPeriod appends a new period to the history, dropping the oldest one if it's full.
+func (s *sequencer) recordPeriod(periodID compose.PeriodID, targetSuperblockNumber compose.SuperblockNumber) {
+	// Caller must hold the s mutex
+	if s.periodHistorySize <= 0 {
+		return
+	}
+	if len(s.History) == s.periodHistorySize {
+		copy(s.History, s.History[1:])
+		s.History = s.History[:len(s.History)-1]
+	}
+	s.History = append(s.History, PeriodRecord{
+		PeriodID:               periodID,
+		TargetSuperblockNumber: targetSuperblockNumber,
+	})
+}
+
+// recordSeal sets the sealed block of its period in the history, if the period is still tracked.
+func (s *sequencer) recordSeal(sealed SealedBlockHeader) {
+	// Caller must hold the s mutex
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if s.History[i].PeriodID == sealed.PeriodID {
+			s.History[i].SealedBlock = &sealed
+			return
+		}
+	}
 }
 
 // BeginBlock is a hook called at the start of a new L2 block.
@@ -191,18 +426,19 @@ func (s *sequencer) BeginBlock(blockNumber BlockNumber) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.PendingBlock != nil {
+	if _, ok := s.PendingBlocks[s.PeriodID]; ok {
 		return ErrBlockAlreadyOpen
 	}
 
-	if blockNumber != s.Head+1 {
+	// Block numbers are sequential across periods, including blocks that are still open.
+	if blockNumber != s.lastBlockNumber()+1 {
 		return ErrBlockNotSequential
 	}
 
 	s.logger.Info().Uint64("new_block_number", uint64(blockNumber)).Msg("Beginning block")
 
 	// Add immutable tags to the new block
-	s.PendingBlock = &PendingBlock{
+	s.PendingBlocks[s.PeriodID] = PendingBlock{
 		Number:           blockNumber,
 		PeriodID:         s.PeriodID,
 		SuperblockNumber: s.TargetSuperblockNumber,
@@ -214,7 +450,7 @@ func (s *sequencer) BeginBlock(blockNumber BlockNumber) error {
 func (s *sequencer) CanIncludeLocalTx() (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.PendingBlock == nil {
+	if len(s.PendingBlocks) == 0 {
 		return false, ErrNoPendingBlock
 	}
 	return s.ActiveInstanceID == nil, nil
@@ -228,14 +464,25 @@ func (s *sequencer) OnStartInstance(
 ) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.PendingBlock == nil {
+	if len(s.PendingBlocks) == 0 {
 		return ErrNoPendingBlock
 	}
 	if s.ActiveInstanceID != nil {
 		return ErrActiveInstanceExists
 	}
 
-	if s.PendingBlock.PeriodID != periodID {
+	// An instance from a future period means this sequencer missed a StartPeriod and must resync.
+	if periodID > s.PeriodID {
+		s.logger.Warn().
+			Uint64("instance_period_id", uint64(periodID)).
+			Uint64("current_period_id", uint64(s.PeriodID)).
+			Msg("Received instance for a future period, sequencer must resync")
+		return fmt.Errorf("instance period %d, current period %d: %w: %w",
+			periodID, s.PeriodID, ErrFuturePeriod, ErrPeriodIDMismatch)
+	}
+
+	// The instance must be included in the pending block of its period.
+	if _, ok := s.PendingBlocks[periodID]; !ok {
 		return ErrPeriodIDMismatch
 	}
 
@@ -253,29 +500,54 @@ func (s *sequencer) OnStartInstance(
 	return nil
 }
 
+// ActiveInstance returns the ID of the active instance (set by OnStartInstance until decided or rolled back),
+// and whether there is one.
+func (s *sequencer) ActiveInstance() (compose.InstanceID, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ActiveInstanceID == nil {
+		return compose.InstanceID{}, false
+	}
+	return *s.ActiveInstanceID, true
+}
+
 // OnDecidedInstance sets the active instance to nil, unlocking local tx inclusion (SCP decision hook).
 func (s *sequencer) OnDecidedInstance(id compose.InstanceID) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	if s.ActiveInstanceID == nil {
+		s.mu.Unlock()
 		return ErrNoActiveInstance
 	}
 	if *s.ActiveInstanceID != id {
+		s.mu.Unlock()
 		return ErrActiveInstanceMismatch
 	}
 	s.logger.Info().
 		Msg("Decided active instance, unlocking local tx inclusion")
 	s.ActiveInstanceID = nil
+	s.mu.Unlock()
+
+	s.notifyLocalTxUnlocked()
 	return nil
 }
 
+// notifyLocalTxUnlocked fires the local tx unlock callback, if set.
+// Must be called without holding the s mutex.
+func (s *sequencer) notifyLocalTxUnlocked() {
+	if s.onLocalTxUnlocked != nil {
+		s.onLocalTxUnlocked()
+	}
+}
+
 func (s *sequencer) EndBlock(ctx context.Context, b BlockHeader) error {
 	s.mu.Lock()
-	if s.PendingBlock == nil {
+	if len(s.PendingBlocks) == 0 {
 		s.mu.Unlock()
 		return ErrNoPendingBlock
 	}
-	if s.PendingBlock.Number != b.Number {
+	// Blocks are sealed in order, so only the oldest pending block can be sealed.
+	pendingBlock, ok := s.oldestPendingBlock()
+	if !ok || pendingBlock.Number != b.Number {
 		s.mu.Unlock()
 		return ErrBlockSealMismatch
 	}
@@ -283,19 +555,29 @@ func (s *sequencer) EndBlock(ctx context.Context, b BlockHeader) error {
 		s.mu.Unlock()
 		return ErrActiveInstanceExists
 	}
+	if s.validateHeaders {
+		if err := validateBlockHeader(b); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	}
 
 	s.logger.Info().Msg("Ending block")
-	s.SealedBlockHead[s.PendingBlock.PeriodID] = SealedBlockHeader{
+	sealed := SealedBlockHeader{
 		BlockHeader:      b,
-		PeriodID:         s.PendingBlock.PeriodID,
-		SuperblockNumber: s.PendingBlock.SuperblockNumber,
+		PeriodID:         pendingBlock.PeriodID,
+		SuperblockNumber: pendingBlock.SuperblockNumber,
+	}
+	s.SealedBlockHead[pendingBlock.PeriodID] = sealed
+	if _, ok := s.SealedBlockFirst[pendingBlock.PeriodID]; !ok {
+		s.SealedBlockFirst[pendingBlock.PeriodID] = b.Number
 	}
+	s.recordSeal(sealed)
 
-	shouldStartSettlement := s.PendingBlock.PeriodID < s.PeriodID
-	settlementPeriod := s.PeriodID - 1
-	settlementSuperblock := s.TargetSuperblockNumber - 1
+	shouldStartSettlement := pendingBlock.PeriodID < s.PeriodID
+	currentPeriod, currentTarget := s.PeriodID, s.TargetSuperblockNumber
 
-	s.PendingBlock = nil
+	delete(s.PendingBlocks, pendingBlock.PeriodID)
 	s.Head = b.Number
 
 	s.mu.Unlock()
@@ -304,22 +586,24 @@ func (s *sequencer) EndBlock(ctx context.Context, b BlockHeader) error {
 	// therefore it's time to request proofs for it.
 	if shouldStartSettlement {
 		s.logger.Info().Msg("Period was ahead of sealed block, triggering settlement pipeline")
-		return s.startSettlement(ctx, settlementPeriod, settlementSuperblock)
+		return s.settlePreviousPeriod(ctx, currentPeriod, currentTarget)
 	}
 	return nil
 }
 
 // AdvanceSettledState advances the settled state to the given block header.
-func (s *sequencer) AdvanceSettledState(settledBlock SettledState) {
+// If it's not ahead of the current settled state, it's ignored and ErrOldSettledState is returned.
+func (s *sequencer) AdvanceSettledState(settledBlock SettledState) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if settledBlock.SuperblockNumber <= s.SettledState.SuperblockNumber {
-		return
+		return ErrOldSettledState
 	}
 	s.logger.Info().
 		Uint64("new_settled_superblock_number", uint64(settledBlock.SuperblockNumber)).
 		Msg("Advancing settled state")
 	s.SettledState = settledBlock
+	return nil
 }
 
 // Rollback message is sent by the publisher to all sequencers.
@@ -330,8 +614,8 @@ func (s *sequencer) Rollback(
 	currentPeriodID compose.PeriodID,
 ) (BlockHeader, error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	if superblockNumber != s.SettledState.SuperblockNumber || superblockHash != s.SettledState.SuperblockHash {
+		s.mu.Unlock()
 		return BlockHeader{}, ErrMismatchedFinalizedState
 	}
 
@@ -343,11 +627,14 @@ func (s *sequencer) Rollback(
 	for blockPeriodID, sealedBlock := range s.SealedBlockHead {
 		if sealedBlock.SuperblockNumber > s.SettledState.SuperblockNumber {
 			delete(s.SealedBlockHead, blockPeriodID)
+			delete(s.SealedBlockFirst, blockPeriodID)
 		}
 	}
 
-	// Discard current block and active instance
-	s.PendingBlock = nil
+	// Discard in-flight settlements, pending blocks and active instance
+	clear(s.SettlingSuperblocks)
+	unlocked := s.ActiveInstanceID != nil
+	s.PendingBlocks = make(map[compose.PeriodID]PendingBlock)
 	s.ActiveInstanceID = nil
 	s.Head = s.SettledState.BlockHeader.Number
 
@@ -355,5 +642,91 @@ func (s *sequencer) Rollback(
 	s.PeriodID = currentPeriodID
 	s.TargetSuperblockNumber = s.SettledState.SuperblockNumber + 1
 
-	return s.SettledState.BlockHeader, nil
+	head := s.SettledState.BlockHeader
+	s.mu.Unlock()
+
+	if unlocked {
+		s.notifyLocalTxUnlocked()
+	}
+	return head, nil
+}
+
+// validateBlockHeader checks that the header of a block being sealed is complete.
+func validateBlockHeader(b BlockHeader) error {
+	if b.BlockHash.IsZero() {
+		return fmt.Errorf("block %d has a zero block hash: %w", b.Number, ErrInvalidBlockHeader)
+	}
+	if b.StateRoot.IsZero() {
+		return fmt.Errorf("block %d has a zero state root: %w", b.Number, ErrInvalidBlockHeader)
+	}
+	return nil
+}
+
+// lastBlockNumber returns the highest block number, either sealed (head) or still pending.
+func (s *sequencer) lastBlockNumber() BlockNumber {
+	// Caller must hold the s mutex
+	last := s.Head
+	for _, block := range s.PendingBlocks {
+		last = max(last, block.Number)
+	}
+	return last
+}
+
+// oldestPendingBlock returns the pending block with the lowest block number.
+func (s *sequencer) oldestPendingBlock() (PendingBlock, bool) {
+	// Caller must hold the s mutex
+	var oldest PendingBlock
+	found := false
+	for _, block := range s.PendingBlocks {
+		if !found || block.Number < oldest.Number {
+			oldest = block
+			found = true
+		}
+	}
+	return oldest, found
+}
+
+// ValidateSealedChain checks that the sealed blocks, ordered by period, form a contiguous chain:
+// block numbers must never go beyond the head, and the first block of each period must directly follow
+// the last block of the previous sealed period (periods whose first block isn't tracked must have a single block).
+// Superblock numbers must strictly increase, incrementing by one between consecutive periods.
+// A violation indicates a lost or corrupted seal.
+func (s *sequencer) ValidateSealedChain() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	periods := make([]compose.PeriodID, 0, len(s.SealedBlockHead))
+	for periodID := range s.SealedBlockHead {
+		periods = append(periods, periodID)
+	}
+	slices.Sort(periods)
+
+	for i, periodID := range periods {
+		block := s.SealedBlockHead[periodID]
+		if block.BlockHeader.Number > s.Head {
+			return fmt.Errorf("period %d sealed block %d is beyond head %d: %w",
+				periodID, block.BlockHeader.Number, s.Head, ErrSealedChainNotContiguous)
+		}
+		if i == 0 {
+			continue
+		}
+
+		prevPeriodID := periods[i-1]
+		prev := s.SealedBlockHead[prevPeriodID]
+		first, ok := s.SealedBlockFirst[periodID]
+		if !ok {
+			first = block.BlockHeader.Number
+		}
+		if first != prev.BlockHeader.Number+1 || block.BlockHeader.Number < first {
+			return fmt.Errorf("period %d sealed blocks %d-%d do not follow period %d sealed block %d: %w",
+				periodID, first, block.BlockHeader.Number, prevPeriodID, prev.BlockHeader.Number,
+				ErrSealedChainNotContiguous)
+		}
+		if block.SuperblockNumber <= prev.SuperblockNumber ||
+			(periodID == prevPeriodID+1 && block.SuperblockNumber != prev.SuperblockNumber+1) {
+			return fmt.Errorf("period %d superblock %d does not follow period %d superblock %d: %w",
+				periodID, block.SuperblockNumber, prevPeriodID, prev.SuperblockNumber, ErrSealedChainNotContiguous)
+		}
+	}
+	return nil
 }
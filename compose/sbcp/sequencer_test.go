@@ -1,6 +1,7 @@
 package sbcp
 
 import (
+	"errors"
 	"io"
 	"testing"
 
@@ -20,10 +21,11 @@ func newSequencerForTest(
 	period compose.PeriodID,
 	target compose.SuperblockNumber,
 	settled SettledState,
+	opts ...SequencerOption,
 ) (*sequencer, *fakeSequencerProver, *fakeSequencerMessenger) {
 	prover := &fakeSequencerProver{}
 	messenger := &fakeSequencerMessenger{}
-	seq := NewSequencer(prover, messenger, period, target, settled, testLogger())
+	seq := NewSequencer(prover, messenger, period, target, settled, testLogger(), opts...)
 	s, ok := seq.(*sequencer)
 	if !ok {
 		panic("NewSequencer did not return *sequencer")
@@ -38,7 +40,7 @@ func TestSequencer_NewSequencer_initial_state(t *testing.T) {
 	assert.Equal(t, compose.PeriodID(10), s.PeriodID)
 	assert.Equal(t, compose.SuperblockNumber(11), s.TargetSuperblockNumber)
 	assert.Equal(t, BlockNumber(100), s.Head)
-	assert.Nil(t, s.PendingBlock)
+	assert.Empty(t, s.PendingBlocks)
 	assert.Nil(t, s.ActiveInstanceID)
 	assert.Empty(t, s.SealedBlockHead)
 }
@@ -48,10 +50,11 @@ func TestSequencer_BeginBlock_ok_and_errors(t *testing.T) {
 
 	// OK path
 	require.NoError(t, s.BeginBlock(11)) // Head=10 => next=11
-	require.NotNil(t, s.PendingBlock)
-	assert.Equal(t, BlockNumber(11), s.PendingBlock.Number)
-	assert.Equal(t, compose.PeriodID(5), s.PendingBlock.PeriodID)
-	assert.Equal(t, compose.SuperblockNumber(6), s.PendingBlock.SuperblockNumber)
+	pending, ok := s.PendingBlocks[compose.PeriodID(5)]
+	require.True(t, ok)
+	assert.Equal(t, BlockNumber(11), pending.Number)
+	assert.Equal(t, compose.PeriodID(5), pending.PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(6), pending.SuperblockNumber)
 
 	// Already open
 	require.ErrorIs(t, s.BeginBlock(12), ErrBlockAlreadyOpen)
@@ -99,6 +102,23 @@ func TestSequencer_CanIncludeLocalTx_and_InstanceHooks(t *testing.T) {
 	require.ErrorIs(t, s.OnDecidedInstance(id), ErrNoActiveInstance)
 }
 
+func TestSequencer_ActiveInstance(t *testing.T) {
+	s, _, _ := newSequencerForTest(compose.PeriodID(7), compose.SuperblockNumber(8), mkSettled(2, 20))
+	require.NoError(t, s.BeginBlock(21))
+	_, ok := s.ActiveInstance()
+	assert.False(t, ok)
+
+	id := compose.InstanceID{1}
+	require.NoError(t, s.OnStartInstance(id, s.PeriodID, compose.SequenceNumber(1)))
+	active, ok := s.ActiveInstance()
+	require.True(t, ok)
+	assert.Equal(t, id, active)
+
+	require.NoError(t, s.OnDecidedInstance(id))
+	_, ok = s.ActiveInstance()
+	assert.False(t, ok)
+}
+
 func TestSequencer_EndBlock_seals_and_updates_head(t *testing.T) {
 	s, _, _ := newSequencerForTest(compose.PeriodID(3), compose.SuperblockNumber(4), mkSettled(1, 30))
 
@@ -108,7 +128,7 @@ func TestSequencer_EndBlock_seals_and_updates_head(t *testing.T) {
 
 	// Seal ok
 	require.NoError(t, s.EndBlock(t.Context(), mkHeader(31)))
-	assert.Nil(t, s.PendingBlock)
+	assert.Empty(t, s.PendingBlocks)
 	assert.Equal(t, BlockNumber(31), s.Head)
 	// SealedBlockHead entry for current period exists
 	sb, ok := s.SealedBlockHead[s.PeriodID]
@@ -116,6 +136,36 @@ func TestSequencer_EndBlock_seals_and_updates_head(t *testing.T) {
 	assert.Equal(t, BlockNumber(31), sb.BlockHeader.Number)
 }
 
+func TestSequencer_EndBlock_header_validation(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(5), compose.SuperblockNumber(6), mkSettled(3, 10))
+		require.NoError(t, s.BeginBlock(11))
+		require.NoError(t, s.EndBlock(t.Context(), mkHeader(11)))
+	})
+
+	t.Run("rejects zero hash or state root", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(
+			compose.PeriodID(5),
+			compose.SuperblockNumber(6),
+			mkSettled(3, 10),
+			WithHeaderValidation(),
+		)
+		require.NoError(t, s.BeginBlock(11))
+
+		header := mkHeader(11)
+		require.ErrorIs(t, s.EndBlock(t.Context(), header), ErrInvalidBlockHeader)
+
+		header.BlockHash = compose.BlockHash{1}
+		require.ErrorIs(t, s.EndBlock(t.Context(), header), ErrInvalidBlockHeader)
+		assert.Contains(t, s.PendingBlocks, compose.PeriodID(5))
+		assert.Equal(t, BlockNumber(10), s.Head)
+
+		header.StateRoot = compose.StateRoot{2}
+		require.NoError(t, s.EndBlock(t.Context(), header))
+		assert.Equal(t, BlockNumber(11), s.Head)
+	})
+}
+
 func TestSequencer_EndBlock_rejects_active_instance(t *testing.T) {
 	s, _, _ := newSequencerForTest(compose.PeriodID(3), compose.SuperblockNumber(4), mkSettled(1, 30))
 
@@ -197,8 +247,50 @@ func TestSequencer_StartPeriod_triggers_immediate_settlement_when_no_pending(t *
 	assert.Equal(t, []byte("seq-proof"), messenger.proofs[0].proof)
 }
 
+func TestSequencer_StartPeriod_genesis(t *testing.T) {
+	s, p, _ := newSequencerForTest(compose.PeriodID(0), compose.SuperblockNumber(0), mkSettled(0, 0))
+
+	// Period 0 can start, without settling any previous period
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(0), compose.SuperblockNumber(1)))
+	assert.Equal(t, compose.PeriodID(0), s.PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(1), s.TargetSuperblockNumber)
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(1), compose.SuperblockNumber(0)))
+	assert.Equal(t, compose.PeriodID(1), s.PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(0), s.TargetSuperblockNumber)
+	assert.Empty(t, p.calls)
+
+	// Period 1 settles period 0 and superblock 0
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(1), compose.SuperblockNumber(1)))
+	require.Len(t, p.calls, 1)
+	assert.Equal(t, compose.SuperblockNumber(0), p.calls[0].sb)
+}
+
+func TestSequencer_Settlement_superseded_by_rollback(t *testing.T) {
+	settled := mkSettled(6, 50)
+	s, p, messenger := newSequencerForTest(compose.PeriodID(10), compose.SuperblockNumber(11), settled)
+	p.nextProof = []byte("seq-proof")
+	// The publisher rolls back while the proof for superblock 11 is being generated
+	p.onRequest = func() {
+		_, err := s.Rollback(settled.SuperblockNumber, settled.SuperblockHash, compose.PeriodID(11))
+		require.NoError(t, err)
+	}
+
+	err := s.StartPeriod(t.Context(), compose.PeriodID(11), compose.SuperblockNumber(12))
+	require.ErrorIs(t, err, ErrSettlementSuperseded)
+	require.Len(t, p.calls, 1)
+	assert.Empty(t, messenger.proofs)
+	assert.Empty(t, s.SettlingSuperblocks)
+
+	// Later settlements are unaffected
+	p.onRequest = nil
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(12), compose.SuperblockNumber(8)))
+	require.Len(t, messenger.proofs, 1)
+	assert.Equal(t, compose.SuperblockNumber(7), messenger.proofs[0].superblockNumber)
+	assert.Empty(t, s.SettlingSuperblocks)
+}
+
 func TestSequencer_StartPeriod_active_instance_does_not_defer_in_impl(t *testing.T) {
-	// Spec would defer on active instance; impl only checks PendingBlock.
+	// Spec would defer on active instance; impl only checks PendingBlocks.
 	s, p, _ := newSequencerForTest(compose.PeriodID(2), compose.SuperblockNumber(3), mkSettled(1, 10))
 	// No pending block, set active instance only
 	s.ActiveInstanceID = &compose.InstanceID{1}
@@ -222,13 +314,41 @@ func TestSequencer_ReceiveXTRequest_forwardsToPublisher(t *testing.T) {
 	assert.Equal(t, req, messenger.requests[0])
 }
 
+func TestSequencer_ReceiveXTRequest_rejectsMalformed(t *testing.T) {
+	s, _, messenger := newSequencerForTest(compose.PeriodID(4), compose.SuperblockNumber(5), mkSettled(2, 10))
+
+	err := s.ReceiveXTRequest(t.Context(), makeXTRequest(
+		chainReq(1, []byte("a")),
+		chainReq(1, []byte("b")),
+	))
+	require.ErrorIs(t, err, ErrInvalidRequest)
+	require.ErrorIs(t, err, compose.ErrDuplicatedChain)
+
+	err = s.ReceiveXTRequest(t.Context(), compose.XTRequest{})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+	require.ErrorIs(t, err, compose.ErrEmptyRequest)
+
+	assert.Empty(t, messenger.requests)
+}
+
+func TestSequencer_ReceiveXTRequest_propagatesForwardError(t *testing.T) {
+	s, _, messenger := newSequencerForTest(compose.PeriodID(4), compose.SuperblockNumber(5), mkSettled(2, 10))
+	messenger.forwardErr = errors.New("publisher unreachable")
+
+	err := s.ReceiveXTRequest(t.Context(), makeXTRequest(chainReq(1, []byte("a"))))
+	require.ErrorIs(t, err, messenger.forwardErr)
+}
+
 func TestSequencer_AdvanceSettledState_monotonic(t *testing.T) {
 	s, _, _ := newSequencerForTest(compose.PeriodID(1), compose.SuperblockNumber(2), mkSettled(1, 5))
 	// No update for same number
-	s.AdvanceSettledState(mkSettled(1, 5))
+	require.ErrorIs(t, s.AdvanceSettledState(mkSettled(1, 5)), ErrOldSettledState)
 	assert.Equal(t, compose.SuperblockNumber(1), s.SettledState.SuperblockNumber)
 	// Advance forward
-	s.AdvanceSettledState(mkSettled(2, 6))
+	require.NoError(t, s.AdvanceSettledState(mkSettled(2, 6)))
+	assert.Equal(t, compose.SuperblockNumber(2), s.SettledState.SuperblockNumber)
+	// Backward is not applied
+	require.ErrorIs(t, s.AdvanceSettledState(mkSettled(1, 5)), ErrOldSettledState)
 	assert.Equal(t, compose.SuperblockNumber(2), s.SettledState.SuperblockNumber)
 }
 
@@ -269,7 +389,7 @@ func TestSequencer_Rollback_discards_newer_and_resets(t *testing.T) {
 	head, err := s.Rollback(4, settled.SuperblockHash, compose.PeriodID(12))
 	require.NoError(t, err)
 	assert.Equal(t, BlockNumber(100), head.Number)
-	assert.Nil(t, s.PendingBlock)
+	assert.Empty(t, s.PendingBlocks)
 	assert.Nil(t, s.ActiveInstanceID)
 	// Blocks with SB > 4 removed
 	_, ok := s.SealedBlockHead[10]
@@ -294,6 +414,21 @@ func TestSequencer_OnStartInstance_validations(t *testing.T) {
 		require.NoError(t, s.BeginBlock(41))
 		err := s.OnStartInstance(compose.InstanceID{2}, s.PeriodID+1, compose.SequenceNumber(1))
 		require.ErrorIs(t, err, ErrPeriodIDMismatch)
+		require.ErrorIs(t, err, ErrFuturePeriod)
+		require.ErrorIs(t, err, compose.ErrPeriodMismatch)
+	})
+
+	t.Run("rejects future period while previous period block is open", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(5), compose.SuperblockNumber(6), mkSettled(2, 40))
+		require.NoError(t, s.BeginBlock(41))
+		require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(6), compose.SuperblockNumber(7)))
+		require.NoError(t, s.BeginBlock(42))
+
+		// Missed StartPeriod(7)
+		err := s.OnStartInstance(compose.InstanceID{2}, compose.PeriodID(7), compose.SequenceNumber(1))
+		require.ErrorIs(t, err, ErrFuturePeriod)
+		assert.Nil(t, s.ActiveInstanceID)
+		require.NoError(t, s.OnStartInstance(compose.InstanceID{2}, compose.PeriodID(6), compose.SequenceNumber(1)))
 	})
 
 	t.Run("rejects when period mismatch (lower)", func(t *testing.T) {
@@ -301,6 +436,7 @@ func TestSequencer_OnStartInstance_validations(t *testing.T) {
 		require.NoError(t, s.BeginBlock(51))
 		err := s.OnStartInstance(compose.InstanceID{3}, s.PeriodID-1, compose.SequenceNumber(1))
 		require.ErrorIs(t, err, ErrPeriodIDMismatch)
+		require.ErrorIs(t, err, compose.ErrPeriodMismatch)
 	})
 
 	t.Run("enforces strictly increasing sequence numbers", func(t *testing.T) {
@@ -333,3 +469,252 @@ func TestSequencer_OnStartInstance_validations(t *testing.T) {
 		)
 	})
 }
+
+func TestSequencer_ValidateSealedChain(t *testing.T) {
+	t.Run("contiguous chain is valid", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(9), compose.SuperblockNumber(10), mkSettled(5, 40))
+
+		require.NoError(t, s.BeginBlock(41))
+		require.NoError(t, s.EndBlock(t.Context(), mkHeader(41)))
+		require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(10), compose.SuperblockNumber(11)))
+		require.NoError(t, s.BeginBlock(42))
+		require.NoError(t, s.EndBlock(t.Context(), mkHeader(42)))
+		require.NoError(t, s.BeginBlock(43))
+		require.NoError(t, s.EndBlock(t.Context(), mkHeader(43)))
+
+		require.NoError(t, s.ValidateSealedChain())
+	})
+
+	t.Run("block number regression is detected", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(10), compose.SuperblockNumber(11), mkSettled(5, 40))
+		s.Head = 43
+		s.SealedBlockHead[9] = SealedBlockHeader{BlockHeader: mkHeader(43), PeriodID: 9, SuperblockNumber: 10}
+		// Period 10 seal was lost and replaced by a stale block
+		s.SealedBlockHead[10] = SealedBlockHeader{BlockHeader: mkHeader(42), PeriodID: 10, SuperblockNumber: 11}
+
+		require.ErrorIs(t, s.ValidateSealedChain(), ErrSealedChainNotContiguous)
+	})
+
+	t.Run("block number gap is detected", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(10), compose.SuperblockNumber(11), mkSettled(5, 40))
+		s.Head = 43
+		s.SealedBlockHead[9] = SealedBlockHeader{BlockHeader: mkHeader(41), PeriodID: 9, SuperblockNumber: 10}
+		// The seal of block 42 was lost
+		s.SealedBlockHead[10] = SealedBlockHeader{BlockHeader: mkHeader(43), PeriodID: 10, SuperblockNumber: 11}
+
+		require.ErrorIs(t, s.ValidateSealedChain(), ErrSealedChainNotContiguous)
+	})
+
+	t.Run("block number gap within tracked periods is detected", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(10), compose.SuperblockNumber(11), mkSettled(5, 40))
+		s.Head = 45
+		s.SealedBlockHead[9] = SealedBlockHeader{BlockHeader: mkHeader(42), PeriodID: 9, SuperblockNumber: 10}
+		s.SealedBlockFirst[9] = 41
+		// Period 10 sealed blocks 44 and 45, but block 43 was lost
+		s.SealedBlockHead[10] = SealedBlockHeader{BlockHeader: mkHeader(45), PeriodID: 10, SuperblockNumber: 11}
+		s.SealedBlockFirst[10] = 44
+
+		require.ErrorIs(t, s.ValidateSealedChain(), ErrSealedChainNotContiguous)
+
+		s.SealedBlockFirst[10] = 43
+		require.NoError(t, s.ValidateSealedChain())
+	})
+
+	t.Run("block beyond head is detected", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(10), compose.SuperblockNumber(11), mkSettled(5, 40))
+		s.SealedBlockHead[9] = SealedBlockHeader{BlockHeader: mkHeader(41), PeriodID: 9, SuperblockNumber: 10}
+
+		require.ErrorIs(t, s.ValidateSealedChain(), ErrSealedChainNotContiguous)
+	})
+
+	t.Run("superblock gap is detected", func(t *testing.T) {
+		s, _, _ := newSequencerForTest(compose.PeriodID(10), compose.SuperblockNumber(12), mkSettled(5, 40))
+		s.Head = 42
+		s.SealedBlockHead[9] = SealedBlockHeader{BlockHeader: mkHeader(41), PeriodID: 9, SuperblockNumber: 10}
+		s.SealedBlockHead[10] = SealedBlockHeader{BlockHeader: mkHeader(42), PeriodID: 10, SuperblockNumber: 12}
+
+		require.ErrorIs(t, s.ValidateSealedChain(), ErrSealedChainNotContiguous)
+	})
+}
+
+func TestSequencer_PendingBlocks_across_period_boundary(t *testing.T) {
+	s, p, messenger := newSequencerForTest(compose.PeriodID(9), compose.SuperblockNumber(10), mkSettled(5, 40))
+	p.nextProof = []byte("seq-proof")
+
+	// Open a block in period 9
+	require.NoError(t, s.BeginBlock(41))
+
+	// Period rolls to 10 while the block is still open
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(10), compose.SuperblockNumber(11)))
+	assert.Empty(t, p.calls)
+
+	// A block for period 10 can be opened, keeping block numbers sequential
+	require.ErrorIs(t, s.BeginBlock(41), ErrBlockNotSequential)
+	require.NoError(t, s.BeginBlock(42))
+	require.ErrorIs(t, s.BeginBlock(43), ErrBlockAlreadyOpen)
+	require.Len(t, s.PendingBlocks, 2)
+	assert.Equal(t, compose.PeriodID(9), s.PendingBlocks[9].PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(10), s.PendingBlocks[9].SuperblockNumber)
+	assert.Equal(t, compose.PeriodID(10), s.PendingBlocks[10].PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(11), s.PendingBlocks[10].SuperblockNumber)
+
+	// Blocks must be sealed in order
+	require.ErrorIs(t, s.EndBlock(t.Context(), mkHeader(42)), ErrBlockSealMismatch)
+
+	// Sealing the period 9 block triggers its settlement
+	require.NoError(t, s.EndBlock(t.Context(), mkHeader(41)))
+	require.Len(t, p.calls, 1)
+	assert.Equal(t, compose.SuperblockNumber(10), p.calls[0].sb)
+	require.Len(t, messenger.proofs, 1)
+	assert.Equal(t, compose.PeriodID(9), messenger.proofs[0].periodID)
+
+	// Sealing the period 10 block doesn't trigger settlement
+	require.NoError(t, s.EndBlock(t.Context(), mkHeader(42)))
+	assert.Len(t, p.calls, 1)
+	assert.Empty(t, s.PendingBlocks)
+	assert.Equal(t, BlockNumber(42), s.Head)
+	assert.Equal(t, BlockNumber(41), s.SealedBlockHead[9].BlockHeader.Number)
+	assert.Equal(t, BlockNumber(42), s.SealedBlockHead[10].BlockHeader.Number)
+}
+
+func TestSequencer_OnLocalTxUnlocked_hook(t *testing.T) {
+	unlocks := 0
+	settled := mkSettled(4, 100)
+	var s *sequencer
+	s, _, _ = newSequencerForTest(
+		compose.PeriodID(9),
+		compose.SuperblockNumber(10),
+		settled,
+		WithOnLocalTxUnlocked(func() {
+			// Calling back into the sequencer must not deadlock
+			ok, err := s.CanIncludeLocalTx()
+			if err == nil {
+				assert.True(t, ok)
+			}
+			unlocks++
+		}),
+	)
+
+	// Beginning a block doesn't fire the hook
+	require.NoError(t, s.BeginBlock(101))
+	assert.Zero(t, unlocks)
+
+	// Deciding the active instance fires the hook
+	require.NoError(t, s.OnStartInstance(compose.InstanceID{1}, s.PeriodID, compose.SequenceNumber(1)))
+	require.NoError(t, s.OnDecidedInstance(compose.InstanceID{1}))
+	assert.Equal(t, 1, unlocks)
+
+	// Rolling back with an active instance fires the hook
+	require.NoError(t, s.OnStartInstance(compose.InstanceID{2}, s.PeriodID, compose.SequenceNumber(2)))
+	_, err := s.Rollback(settled.SuperblockNumber, settled.SuperblockHash, compose.PeriodID(10))
+	require.NoError(t, err)
+	assert.Equal(t, 2, unlocks)
+
+	// Rolling back without an active instance doesn't fire the hook
+	_, err = s.Rollback(settled.SuperblockNumber, settled.SuperblockHash, compose.PeriodID(10))
+	require.NoError(t, err)
+	assert.Equal(t, 2, unlocks)
+}
+
+func TestSequencer_SettlementStatus(t *testing.T) {
+	s, p, _ := newSequencerForTest(compose.PeriodID(5), compose.SuperblockNumber(6), mkSettled(3, 10))
+
+	status := s.SettlementStatus()
+	assert.Equal(t, compose.PeriodID(5), status.PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(6), status.TargetSuperblockNumber)
+	assert.False(t, status.WaitingForSeal)
+	assert.False(t, status.InFlight)
+	assert.Nil(t, status.LastProofSentPeriodID)
+
+	// Roll the period with an open block: settlement of period 5 waits for the seal
+	require.NoError(t, s.BeginBlock(11))
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(6), compose.SuperblockNumber(7)))
+	status = s.SettlementStatus()
+	assert.Equal(t, compose.PeriodID(6), status.PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(7), status.TargetSuperblockNumber)
+	assert.True(t, status.WaitingForSeal)
+	assert.Nil(t, status.LastProofSentPeriodID)
+
+	// The proof request is in flight while the prover runs
+	p.onRequest = func() {
+		assert.True(t, s.SettlementStatus().InFlight)
+	}
+	require.NoError(t, s.EndBlock(t.Context(), mkHeader(11)))
+	require.Len(t, p.calls, 1)
+
+	status = s.SettlementStatus()
+	assert.False(t, status.WaitingForSeal)
+	assert.False(t, status.InFlight)
+	if assert.NotNil(t, status.LastProofSentPeriodID) {
+		assert.Equal(t, compose.PeriodID(5), *status.LastProofSentPeriodID)
+	}
+}
+
+func TestSequencer_OnProofSent(t *testing.T) {
+	type sentProof struct {
+		periodID         compose.PeriodID
+		superblockNumber compose.SuperblockNumber
+	}
+	var sent []sentProof
+	s, p, messenger := newSequencerForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(11),
+		mkSettled(6, 50),
+		WithOnProofSent(func(periodID compose.PeriodID, superblockNumber compose.SuperblockNumber) {
+			sent = append(sent, sentProof{periodID, superblockNumber})
+		}),
+	)
+	p.nextProof = []byte("seq-proof")
+
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(11), compose.SuperblockNumber(12)))
+	require.Len(t, messenger.proofs, 1)
+	assert.Equal(t, []sentProof{{10, 11}}, sent)
+
+	// Not fired when the prover fails
+	p.err = errors.New("prover down")
+	require.Error(t, s.StartPeriod(t.Context(), compose.PeriodID(12), compose.SuperblockNumber(13)))
+	assert.Len(t, messenger.proofs, 1)
+	assert.Len(t, sent, 1)
+}
+
+func TestSequencer_PeriodHistory(t *testing.T) {
+	s, _, _ := newSequencerForTest(
+		compose.PeriodID(5),
+		compose.SuperblockNumber(6),
+		mkSettled(3, 10),
+		WithPeriodHistorySize(3),
+	)
+
+	// Block 11 is sealed in period 5 after period 6 started
+	require.NoError(t, s.BeginBlock(11))
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(6), compose.SuperblockNumber(7)))
+	require.NoError(t, s.EndBlock(t.Context(), mkHeader(11)))
+
+	history := s.PeriodHistory()
+	require.Len(t, history, 2)
+	assert.Equal(t, compose.PeriodID(5), history[0].PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(6), history[0].TargetSuperblockNumber)
+	require.NotNil(t, history[0].SealedBlock)
+	assert.Equal(t, BlockNumber(11), history[0].SealedBlock.BlockHeader.Number)
+	assert.Nil(t, history[1].SealedBlock)
+
+	require.NoError(t, s.BeginBlock(12))
+	require.NoError(t, s.EndBlock(t.Context(), mkHeader(12)))
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(7), compose.SuperblockNumber(8)))
+	require.NoError(t, s.StartPeriod(t.Context(), compose.PeriodID(8), compose.SuperblockNumber(9)))
+
+	// Period 5 was dropped, the rest are kept in order
+	history = s.PeriodHistory()
+	require.Len(t, history, 3)
+	assert.Equal(t, compose.PeriodID(6), history[0].PeriodID)
+	require.NotNil(t, history[0].SealedBlock)
+	assert.Equal(t, BlockNumber(12), history[0].SealedBlock.BlockHeader.Number)
+	assert.Equal(t, compose.PeriodID(7), history[1].PeriodID)
+	assert.Nil(t, history[1].SealedBlock)
+	assert.Equal(t, compose.PeriodID(8), history[2].PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(9), history[2].TargetSuperblockNumber)
+
+	// The returned history is a copy
+	history[0].SealedBlock.BlockHeader.Number = 99
+	assert.Equal(t, BlockNumber(12), s.PeriodHistory()[0].SealedBlock.BlockHeader.Number)
+}
@@ -0,0 +1,224 @@
+package sbcp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/compose-network/specs/compose"
+	"github.com/rs/zerolog"
+)
+
+// PublisherSnapshot is a deep copy of the publisher state at a given point in time.
+// It can be serialized as JSON (proofs as base64, hashes as hex) and used to restore
+// the publisher after a restart through RestorePublisher.
+type PublisherSnapshot struct {
+	PeriodID               compose.PeriodID
+	TargetSuperblockNumber compose.SuperblockNumber
+
+	LastFinalizedSuperblockNumber compose.SuperblockNumber
+	LastFinalizedSuperblockHash   compose.SuperblockHash
+	Proofs                        map[compose.SuperblockNumber]map[compose.ChainID][]byte
+	Chains                        []compose.ChainID
+	AggregatedSuperblocks         []compose.SuperblockNumber
+
+	SequenceNumber               compose.SequenceNumber
+	ActiveChains                 []compose.ChainID
+	RequestQueue                 []compose.XTRequest
+	HighestDecidedSequenceNumber compose.SequenceNumber
+
+	ProofWindow uint64
+}
+
+// Snapshot returns a deep copy of the publisher state.
+func (p *publisher) Snapshot() PublisherSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proofs := make(map[compose.SuperblockNumber]map[compose.ChainID][]byte, len(p.Proofs))
+	for superblockNumber, chainProofs := range p.Proofs {
+		proofs[superblockNumber] = make(map[compose.ChainID][]byte, len(chainProofs))
+		for chainID, proof := range chainProofs {
+			proofs[superblockNumber][chainID] = append([]byte(nil), proof...)
+		}
+	}
+
+	activeChains := make([]compose.ChainID, 0, len(p.ActiveChains))
+	for chainID, active := range p.ActiveChains {
+		if active {
+			activeChains = append(activeChains, chainID)
+		}
+	}
+	slices.Sort(activeChains)
+
+	requestQueue := make([]compose.XTRequest, 0, len(p.RequestQueue))
+	for _, request := range p.RequestQueue {
+		requestQueue = append(requestQueue, request.Clone())
+	}
+
+	return PublisherSnapshot{
+		PeriodID:                      p.PeriodID,
+		TargetSuperblockNumber:        p.TargetSuperblockNumber,
+		LastFinalizedSuperblockNumber: p.LastFinalizedSuperblockNumber,
+		LastFinalizedSuperblockHash:   p.LastFinalizedSuperblockHash,
+		Proofs:                        proofs,
+		Chains:                        slices.Sorted(maps.Keys(p.Chains)),
+		AggregatedSuperblocks:         slices.Sorted(maps.Keys(p.AggregatedSuperblocks)),
+		SequenceNumber:                p.SequenceNumber,
+		ActiveChains:                  activeChains,
+		RequestQueue:                  requestQueue,
+		HighestDecidedSequenceNumber:  p.HighestDecidedSequenceNumber,
+		ProofWindow:                   p.ProofWindow,
+	}
+}
+
+// RestorePublisher rebuilds a publisher from a snapshot (e.g. persisted before a restart).
+// Buffered proofs are restored as if first received at restoration time, so latencies are measured from it.
+func RestorePublisher(
+	snapshot PublisherSnapshot,
+	prover PublisherProver,
+	messenger PublisherMessenger,
+	l1 L1,
+	logger zerolog.Logger,
+	opts ...PublisherOption,
+) (Publisher, error) {
+	chains := make(map[compose.ChainID]struct{}, len(snapshot.Chains))
+	for _, chainID := range snapshot.Chains {
+		chains[chainID] = struct{}{}
+	}
+
+	pub, err := NewPublisher(
+		prover,
+		messenger,
+		l1,
+		snapshot.PeriodID,
+		snapshot.TargetSuperblockNumber,
+		snapshot.LastFinalizedSuperblockNumber,
+		snapshot.LastFinalizedSuperblockHash,
+		snapshot.ProofWindow,
+		logger,
+		chains,
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p := pub.(*publisher)
+	now := p.now()
+	for superblockNumber, chainProofs := range snapshot.Proofs {
+		p.Proofs[superblockNumber] = make(map[compose.ChainID][]byte, len(chainProofs))
+		for chainID, proof := range chainProofs {
+			p.Proofs[superblockNumber][chainID] = append([]byte(nil), proof...)
+		}
+		p.FirstProofReceivedAt[superblockNumber] = now
+	}
+	for _, superblockNumber := range snapshot.AggregatedSuperblocks {
+		p.AggregatedSuperblocks[superblockNumber] = struct{}{}
+	}
+
+	p.SequenceNumber = snapshot.SequenceNumber
+	for _, chainID := range snapshot.ActiveChains {
+		p.ActiveChains[chainID] = true
+	}
+	for _, request := range snapshot.RequestQueue {
+		p.RequestQueue = append(p.RequestQueue, request.Clone())
+	}
+	p.HighestDecidedSequenceNumber = snapshot.HighestDecidedSequenceNumber
+
+	return p, nil
+}
+
+// ImportProofs merges the sequencer proofs buffered in another publisher's snapshot (e.g. handed by a peer
+// while recovering) into the local ones, returning how many proofs were imported.
+// Proofs for finalized, non-terminated or already aggregated superblocks, from unknown chains,
+// or from chains whose proof is already stored are skipped, as well as those failing the proof verifier.
+// If the next superblock to be finalized gets all its proofs, its network proof is requested and published.
+func (p *publisher) ImportProofs(snapshot PublisherSnapshot) int {
+	// Verification may take a while and thus it is done outside locks.
+	verified := make(map[compose.SuperblockNumber]map[compose.ChainID][]byte, len(snapshot.Proofs))
+	for superblockNumber, chainProofs := range snapshot.Proofs {
+		for chainID, proof := range chainProofs {
+			if p.proofVerifier != nil && p.verifyProof(superblockNumber, proof, chainID) != ProofAckAccepted {
+				continue
+			}
+			if _, ok := verified[superblockNumber]; !ok {
+				verified[superblockNumber] = make(map[compose.ChainID][]byte, len(chainProofs))
+			}
+			verified[superblockNumber][chainID] = proof
+		}
+	}
+
+	p.mu.Lock()
+	imported := 0
+	for superblockNumber, chainProofs := range verified {
+		if superblockNumber <= p.LastFinalizedSuperblockNumber || superblockNumber >= p.TargetSuperblockNumber {
+			continue
+		}
+		if _, ok := p.AggregatedSuperblocks[superblockNumber]; ok {
+			continue
+		}
+		for chainID, proof := range chainProofs {
+			if _, ok := p.Chains[chainID]; !ok {
+				continue
+			}
+			if _, ok := p.Proofs[superblockNumber][chainID]; ok {
+				continue
+			}
+			if _, ok := p.Proofs[superblockNumber]; !ok {
+				p.Proofs[superblockNumber] = make(map[compose.ChainID][]byte)
+				p.FirstProofReceivedAt[superblockNumber] = p.now()
+			}
+			p.Proofs[superblockNumber][chainID] = append([]byte(nil), proof...)
+			p.metrics.IncProofReceived(chainID)
+			imported++
+		}
+	}
+
+	p.logger.Info().
+		Int("imported_proofs", imported).
+		Msg("Imported proofs from snapshot")
+
+	p.publishNextIfReady()
+	return imported
+}
+
+// Diff reports the differences between two snapshots (e.g. from two publisher replicas),
+// one human-readable line per differing field, in a deterministic order.
+// It compares periods, targets, finalized state, active chains, and proof counts per superblock.
+func (s PublisherSnapshot) Diff(other PublisherSnapshot) []string {
+	diffs := make([]string, 0)
+	if s.PeriodID != other.PeriodID {
+		diffs = append(diffs, fmt.Sprintf("period_id: %d != %d", s.PeriodID, other.PeriodID))
+	}
+	if s.TargetSuperblockNumber != other.TargetSuperblockNumber {
+		diffs = append(diffs, fmt.Sprintf("target_superblock_number: %d != %d",
+			s.TargetSuperblockNumber, other.TargetSuperblockNumber))
+	}
+	if s.LastFinalizedSuperblockNumber != other.LastFinalizedSuperblockNumber {
+		diffs = append(diffs, fmt.Sprintf("last_finalized_superblock_number: %d != %d",
+			s.LastFinalizedSuperblockNumber, other.LastFinalizedSuperblockNumber))
+	}
+	if s.LastFinalizedSuperblockHash != other.LastFinalizedSuperblockHash {
+		diffs = append(diffs, fmt.Sprintf("last_finalized_superblock_hash: %s != %s",
+			s.LastFinalizedSuperblockHash, other.LastFinalizedSuperblockHash))
+	}
+	if !slices.Equal(s.ActiveChains, other.ActiveChains) {
+		diffs = append(diffs, fmt.Sprintf("active_chains: %v != %v", s.ActiveChains, other.ActiveChains))
+	}
+
+	superblocks := slices.Collect(maps.Keys(s.Proofs))
+	for superblockNumber := range other.Proofs {
+		if _, ok := s.Proofs[superblockNumber]; !ok {
+			superblocks = append(superblocks, superblockNumber)
+		}
+	}
+	slices.Sort(superblocks)
+	for _, superblockNumber := range superblocks {
+		count, otherCount := len(s.Proofs[superblockNumber]), len(other.Proofs[superblockNumber])
+		if count != otherCount {
+			diffs = append(diffs, fmt.Sprintf("proofs[%d]: %d != %d", superblockNumber, count, otherCount))
+		}
+	}
+	return diffs
+}
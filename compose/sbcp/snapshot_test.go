@@ -0,0 +1,140 @@
+package sbcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/compose-network/specs/compose"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublisher_Snapshot_is_deep_copy(t *testing.T) {
+	chains := makeChainSet(compose.ChainID(1), compose.ChainID(2))
+	pub, _, _, _ := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		chains,
+	)
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+	_, err := pub.StartInstance(makeXTRequest(chainReq(2, []byte("a")), chainReq(1, []byte("b"))))
+	require.NoError(t, err)
+	proof := []byte("proof-1")
+	pub.ReceiveProof(compose.PeriodID(11), compose.SuperblockNumber(6), proof, compose.ChainID(1))
+
+	snapshot := pub.Snapshot()
+	assert.Equal(t, compose.PeriodID(12), snapshot.PeriodID)
+	assert.Equal(t, compose.SuperblockNumber(7), snapshot.TargetSuperblockNumber)
+	assert.Equal(t, compose.SuperblockNumber(5), snapshot.LastFinalizedSuperblockNumber)
+	assert.Equal(t, []compose.ChainID{1, 2}, snapshot.Chains)
+	assert.Equal(t, []compose.ChainID{1, 2}, snapshot.ActiveChains)
+	assert.Equal(t, compose.SequenceNumber(1), snapshot.SequenceNumber)
+	assert.Equal(t, []byte("proof-1"), snapshot.Proofs[6][1])
+
+	// Mutating the publisher's proof bytes doesn't affect the snapshot
+	proof[0] = 'X'
+	assert.Equal(t, []byte("proof-1"), snapshot.Proofs[6][1])
+}
+
+func TestPublisher_ImportProofs_completes_superblock(t *testing.T) {
+	metrics := &countingPublisherMetrics{}
+	pub, _, prover, l1 := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		makeChainSet(compose.ChainID(1), compose.ChainID(2)),
+		WithPublisherMetrics(metrics),
+	)
+	prover.nextProof = []byte("network-proof")
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("local-1"), 1))
+
+	peer := PublisherSnapshot{
+		Proofs: map[compose.SuperblockNumber]map[compose.ChainID][]byte{
+			5: {1: []byte("finalized-1")},
+			6: {1: []byte("peer-1"), 2: []byte("peer-2"), 3: []byte("unknown-chain")},
+		},
+	}
+
+	// Only chain 2's proof for superblock 6 is new, and counted as received
+	assert.Equal(t, 1, pub.ImportProofs(peer))
+	assert.Equal(t, map[compose.ChainID]int{1: 1, 2: 1}, metrics.proofsReceived)
+
+	require.Len(t, prover.calls, 1)
+	assert.Equal(t, compose.SuperblockNumber(6), prover.calls[0].superblock)
+	assert.ElementsMatch(t, [][]byte{[]byte("local-1"), []byte("peer-2")}, prover.calls[0].proofs)
+	require.Len(t, l1.published, 1)
+	assert.Equal(t, compose.SuperblockNumber(6), l1.published[0].superblock)
+
+	// Importing again doesn't trigger a new aggregation
+	assert.Equal(t, 0, pub.ImportProofs(peer))
+	assert.Len(t, prover.calls, 1)
+}
+
+func TestRestorePublisher_roundTrip(t *testing.T) {
+	pub, _, _, _ := newPublisherForTest(
+		compose.PeriodID(10),
+		compose.SuperblockNumber(5),
+		compose.SuperblockNumber(5),
+		compose.SuperblockHash{1},
+		0,
+		makeChainSet(compose.ChainID(1), compose.ChainID(2)),
+	)
+	require.NoError(t, pub.StartPeriod())
+	require.NoError(t, pub.StartPeriod())
+	require.Equal(t, ProofAckAccepted, pub.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-1"), 1))
+
+	encoded, err := json.Marshal(pub.Snapshot())
+	require.NoError(t, err)
+	var snapshot PublisherSnapshot
+	require.NoError(t, json.Unmarshal(encoded, &snapshot))
+
+	prover := &fakePublisherProver{nextProof: []byte("network-proof")}
+	l1 := &fakeL1{}
+	restored, err := RestorePublisher(snapshot, prover, &fakePublisherMessenger{}, l1, testLogger())
+	require.NoError(t, err)
+	assert.Equal(t, pub.Snapshot(), restored.Snapshot())
+
+	require.Equal(t, ProofAckAccepted, restored.ReceiveProof(compose.PeriodID(11), 6, []byte("proof-2"), 2))
+
+	require.Len(t, prover.calls, 1)
+	assert.Equal(t, compose.SuperblockNumber(6), prover.calls[0].superblock)
+	assert.ElementsMatch(t, [][]byte{[]byte("proof-1"), []byte("proof-2")}, prover.calls[0].proofs)
+	require.Len(t, l1.published, 1)
+	assert.Equal(t, compose.SuperblockNumber(6), l1.published[0].superblock)
+}
+
+func TestPublisherSnapshot_Diff(t *testing.T) {
+	base := PublisherSnapshot{
+		PeriodID:                      10,
+		TargetSuperblockNumber:        7,
+		LastFinalizedSuperblockNumber: 5,
+		LastFinalizedSuperblockHash:   compose.SuperblockHash{1},
+		Proofs: map[compose.SuperblockNumber]map[compose.ChainID][]byte{
+			6: {1: []byte("p1")},
+		},
+		ActiveChains: []compose.ChainID{1, 2},
+	}
+	assert.Empty(t, base.Diff(base))
+
+	other := base
+	other.TargetSuperblockNumber = 8
+	other.ActiveChains = []compose.ChainID{1, 3}
+	other.Proofs = map[compose.SuperblockNumber]map[compose.ChainID][]byte{
+		6: {1: []byte("p1"), 2: []byte("p2")},
+	}
+
+	assert.Equal(t, []string{
+		"target_superblock_number: 7 != 8",
+		"active_chains: [1 2] != [1 3]",
+		"proofs[6]: 1 != 2",
+	}, base.Diff(other))
+}
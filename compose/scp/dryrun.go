@@ -0,0 +1,80 @@
+package scp
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/compose-network/specs/compose"
+)
+
+// DryRunResult describes what running the instance would do from its current state.
+type DryRunResult struct {
+	// Vote that would be sent (nil if the instance would keep waiting for mailbox reads)
+	Vote *bool
+	// New distinct mailbox write messages that would be sent, in sending order
+	WriteMessages []MailboxMessage
+	// Mailbox reads that would remain unfulfilled
+	PendingReads []MailboxMessageHeader
+}
+
+// DryRun runs the simulation loop as Run would, consuming the already received mailbox messages,
+// but without sending votes nor mailbox messages, and without mutating the instance state.
+// It lets operators validate an instance against the current state before running it.
+// Simulation failures are returned along with the false vote they'd cause, like Run does.
+func (r *sequencerInstance) DryRun() (DryRunResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != SeqStateSimulating {
+		return DryRunResult{}, ErrNotInSimulatingState
+	}
+
+	putInboxMessages := cloneMailboxMessages(r.putInboxMessages)
+	pendingMessages := cloneMailboxMessages(r.pendingMessages)
+	expectedReads := slices.Clone(r.expectedReadRequests)
+	orderedReads := r.orderedReads
+	written := cloneMailboxMessages(r.writtenMessagesCache)
+	result := DryRunResult{WriteMessages: make([]MailboxMessage, 0)}
+	reject := func(err error) (DryRunResult, error) {
+		vote := false
+		result.Vote = &vote
+		result.PendingReads = expectedReads
+		return result, err
+	}
+
+	for {
+		readRequest, writeMessages, _, err := r.simulate(SimulationRequest{
+			PutInboxMessages: cloneMailboxMessages(putInboxMessages),
+			Transactions:     compose.CloneByteSlices(r.txs),
+			Snapshot:         r.vmSnapshot,
+		})
+		if err != nil {
+			return reject(fmt.Errorf("simulating sequencer failed: %w", err))
+		}
+
+		for _, msg := range writeMessages {
+			if !slices.ContainsFunc(written, func(cached MailboxMessage) bool { return r.sameWrite(cached, msg) }) {
+				written = append(written, msg)
+				result.WriteMessages = append(result.WriteMessages, msg)
+			}
+		}
+
+		if readRequest == nil {
+			vote := true
+			result.Vote = &vote
+			result.PendingReads = expectedReads
+			return result, nil
+		}
+		reads, ordered, err := r.expectReads(expectedReads, *readRequest)
+		if err != nil {
+			return reject(err)
+		}
+		expectedReads, orderedReads = reads, ordered
+
+		// Consume the received messages fulfilling expected reads, as consumeReceivedMailboxMessagesAndSimulate
+		if !fulfillExpectedReads(&expectedReads, &pendingMessages, &putInboxMessages, orderedReads) {
+			result.PendingReads = expectedReads
+			return result, nil
+		}
+	}
+}
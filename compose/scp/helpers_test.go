@@ -1,6 +1,10 @@
 package scp
 
 import (
+	"slices"
+	"sync"
+	"time"
+
 	"github.com/compose-network/specs/compose"
 )
 
@@ -20,7 +24,7 @@ type fakePublisherNetwork struct {
 func (f *fakePublisherNetwork) SendStartInstance(instance compose.Instance) {
 	f.startCalled++
 	f.startInstance = instance
-	f.startXT = cloneXTRequest(instance.XTRequest)
+	f.startXT = instance.XTRequest.Clone()
 }
 
 func (f *fakePublisherNetwork) SendDecided(id compose.InstanceID, decided bool) {
@@ -31,6 +35,26 @@ func (f *fakePublisherNetwork) SendDecided(id compose.InstanceID, decided bool)
 	}{ID: id, Value: decided})
 }
 
+// countingPublisherNetwork counts start calls and can be used concurrently (e.g. by rebroadcasts).
+type countingPublisherNetwork struct {
+	mu          sync.Mutex
+	startCalled int
+}
+
+func (f *countingPublisherNetwork) SendStartInstance(compose.Instance) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.startCalled++
+}
+
+func (f *countingPublisherNetwork) SendDecided(compose.InstanceID, bool) {}
+
+func (f *countingPublisherNetwork) starts() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.startCalled
+}
+
 // simulateResp encodes a single response step for the fake engine.
 type simulateResp struct {
 	read  *MailboxMessageHeader
@@ -65,6 +89,24 @@ func (e *fakeExecutionEngine) Simulate(req SimulationRequest) (*MailboxMessageHe
 	return nil, nil, nil
 }
 
+// fakeOriginTrackingEngine implements OriginTrackingEngine, reporting scripted write origins per simulation.
+type fakeOriginTrackingEngine struct {
+	fakeExecutionEngine
+	origins [][]int
+}
+
+func (e *fakeOriginTrackingEngine) SimulateWithOrigins(
+	req SimulationRequest,
+) (*MailboxMessageHeader, []MailboxMessage, []int, error) {
+	call := e.calls
+	read, write, err := e.Simulate(req)
+	var origins []int
+	if call < len(e.origins) {
+		origins = append([]int(nil), e.origins[call]...)
+	}
+	return read, write, origins, err
+}
+
 // fakeSequencerNetwork collects votes and mailbox messages.
 type fakeSequencerNetwork struct {
 	mailboxSent []struct {
@@ -85,15 +127,112 @@ func (n *fakeSequencerNetwork) SendVote(v bool) {
 	n.votes = append(n.votes, v)
 }
 
-func cloneXTRequest(req compose.XTRequest) compose.XTRequest {
-	out := compose.XTRequest{
-		Transactions: make([]compose.TransactionRequest, len(req.Transactions)),
+// fakeMailboxRequester records the requested mailbox headers.
+type fakeMailboxRequester struct {
+	requests []MailboxMessageHeader
+	// Optional callback run on each request (e.g. to deliver the message right away)
+	onRequest func(header MailboxMessageHeader)
+}
+
+func (m *fakeMailboxRequester) RequestMailbox(header MailboxMessageHeader) {
+	m.requests = append(m.requests, header)
+	if m.onRequest != nil {
+		m.onRequest(header)
+	}
+}
+
+// decisionRecorder records the calls to a publisher decision hook.
+type decisionRecorder struct {
+	calls []struct {
+		ID    compose.InstanceID
+		State compose.DecisionState
+		Votes map[compose.ChainID]bool
+	}
+}
+
+func (d *decisionRecorder) hook(
+	id compose.InstanceID,
+	state compose.DecisionState,
+	votes map[compose.ChainID]bool,
+) {
+	d.calls = append(d.calls, struct {
+		ID    compose.InstanceID
+		State compose.DecisionState
+		Votes map[compose.ChainID]bool
+	}{id, state, votes})
+}
+
+// fakeClock is a Clock whose timers only fire when advanced, from the advancing goroutine.
+type fakeClock struct {
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func (t *fakeTimer) Stop() bool {
+	wasActive := !t.stopped
+	t.stopped = true
+	return wasActive
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	timer := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// Advance moves the clock forward, firing the timers due by then.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	for _, timer := range c.timers {
+		if !timer.stopped && !timer.at.After(c.now) {
+			timer.stopped = true
+			timer.f()
+		}
 	}
-	for i, tr := range req.Transactions {
-		out.Transactions[i] = compose.TransactionRequest{
-			ChainID:      tr.ChainID,
-			Transactions: compose.CloneByteSlices(tr.Transactions),
+}
+
+// fakeOrderedReadsEngine implements ExecutionEngine, needing the given reads in order:
+// each simulation misses the first one not in the put inbox messages, or succeeds.
+type fakeOrderedReadsEngine struct {
+	id     compose.ChainID
+	needs  []MailboxMessageHeader
+	calls  int
+	missed int
+}
+
+func (e *fakeOrderedReadsEngine) ChainID() compose.ChainID { return e.id }
+
+func (e *fakeOrderedReadsEngine) Simulate(req SimulationRequest) (*MailboxMessageHeader, []MailboxMessage, error) {
+	e.calls++
+	for i, need := range e.needs {
+		if !slices.ContainsFunc(req.PutInboxMessages, func(msg MailboxMessage) bool {
+			return msg.MailboxMessageHeader.Equal(need)
+		}) {
+			e.missed = i
+			return &need, nil, nil
 		}
 	}
-	return out
+	return nil, nil, nil
+}
+
+// fakeReadHintingEngine implements ReadHintingEngine, hinting the reads needed after the last missed one.
+type fakeReadHintingEngine struct {
+	fakeOrderedReadsEngine
+	// Optional hints overriding the needed reads
+	hints func() []MailboxMessageHeader
+}
+
+func (e *fakeReadHintingEngine) ReadHints() []MailboxMessageHeader {
+	if e.hints != nil {
+		return e.hints()
+	}
+	return slices.Clone(e.needs[e.missed+1:])
 }
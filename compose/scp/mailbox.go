@@ -2,6 +2,7 @@ package scp
 
 import (
 	"bytes"
+	"encoding/binary"
 
 	"github.com/compose-network/specs/compose"
 )
@@ -37,3 +38,24 @@ func (a MailboxMessageHeader) Equal(b MailboxMessageHeader) bool {
 		a.SessionID == b.SessionID &&
 		a.Label == b.Label
 }
+
+// Encode returns a deterministic big-endian encoding of the header, suitable for hashing or signing:
+// session ID, source and destination chain IDs (8 bytes each), sender and receiver (20 bytes each),
+// and the label prefixed with its 4-byte length.
+func (a MailboxMessageHeader) Encode() []byte {
+	buf := make([]byte, 0, 8*3+20*2+4+len(a.Label))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.SessionID))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.SourceChainID))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.DestChainID))
+	buf = append(buf, a.Sender[:]...)
+	buf = append(buf, a.Receiver[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(a.Label)))
+	buf = append(buf, a.Label...)
+	return buf
+}
+
+// Key returns the header encoding as a string, usable as a map key.
+// Two headers have the same key if and only if they are Equal.
+func (a MailboxMessageHeader) Key() string {
+	return string(a.Encode())
+}
@@ -1,11 +1,14 @@
 package scp
 
 import (
+	"encoding/hex"
+	"reflect"
 	"testing"
 
 	"github.com/compose-network/specs/compose"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMailboxMessageHeader_Equal_and_IgnoresData(t *testing.T) {
@@ -57,3 +60,80 @@ func TestMailboxMessageHeader_Equal_and_IgnoresData(t *testing.T) {
 	b.Label = "other"
 	assert.False(t, a.MailboxMessageHeader.Equal(b.MailboxMessageHeader))
 }
+
+func TestMailboxMessageHeader_Encode(t *testing.T) {
+	a := MailboxMessageHeader{
+		SessionID:     10,
+		SourceChainID: 1,
+		DestChainID:   2,
+		Sender:        compose.EthAddress{1},
+		Receiver:      compose.EthAddress{2},
+		Label:         "L",
+	}
+
+	// Equal headers produce identical encodings
+	b := a
+	assert.Equal(t, a.Encode(), b.Encode())
+	assert.Equal(t, a.Key(), b.Key())
+	assert.Len(t, a.Encode(), 8*3+20*2+4+len(a.Label))
+
+	// Flipping any field changes the encoding
+	variants := []func(h *MailboxMessageHeader){
+		func(h *MailboxMessageHeader) { h.SessionID = 999 },
+		func(h *MailboxMessageHeader) { h.SourceChainID = 99 },
+		func(h *MailboxMessageHeader) { h.DestChainID = 99 },
+		func(h *MailboxMessageHeader) { h.Sender = compose.EthAddress{9} },
+		func(h *MailboxMessageHeader) { h.Receiver = compose.EthAddress{9} },
+		func(h *MailboxMessageHeader) { h.Label = "other" },
+		func(h *MailboxMessageHeader) { h.Label = "" },
+	}
+	keys := map[string]struct{}{a.Key(): {}}
+	for _, mutate := range variants {
+		b = a
+		mutate(&b)
+		assert.NotEqual(t, a.Encode(), b.Encode())
+		keys[b.Key()] = struct{}{}
+	}
+	assert.Len(t, keys, len(variants)+1)
+}
+
+func TestMailboxMessageHeader_Encode_LabelIsLengthPrefixed(t *testing.T) {
+	// Labels are the only variable-length field
+	a := MailboxMessageHeader{Label: "ab"}
+	b := MailboxMessageHeader{Label: "a"}
+	assert.NotEqual(t, a.Key(), b.Key())
+	assert.Equal(t, []byte{0, 0, 0, 2, 'a', 'b'}, a.Encode()[8*3+20*2:])
+}
+
+func TestMailboxMessageHeader_LayoutIsStable(t *testing.T) {
+	// Changing the field set or order of the header changes Encode and Equal; update both together.
+	var fields []string
+	typ := reflect.TypeOf(MailboxMessageHeader{})
+	for i := range typ.NumField() {
+		fields = append(fields, typ.Field(i).Name)
+	}
+	assert.Equal(t, []string{"SessionID", "SourceChainID", "DestChainID", "Sender", "Receiver", "Label"}, fields)
+
+	// MailboxMessage embeds the same header type
+	embedded, ok := reflect.TypeOf(MailboxMessage{}).FieldByName("MailboxMessageHeader")
+	require.True(t, ok)
+	assert.True(t, embedded.Anonymous)
+	assert.Equal(t, typ, embedded.Type)
+
+	// Golden encoding
+	header := MailboxMessageHeader{
+		SessionID:     0x0102,
+		SourceChainID: 0x03,
+		DestChainID:   0x04,
+		Sender:        compose.EthAddress{0xaa},
+		Receiver:      compose.EthAddress{0xbb},
+		Label:         "hi",
+	}
+	expected := "0000000000000102" +
+		"0000000000000003" +
+		"0000000000000004" +
+		"aa00000000000000000000000000000000000000" +
+		"bb00000000000000000000000000000000000000" +
+		"00000002" + "6869"
+	assert.Equal(t, expected, hex.EncodeToString(header.Encode()))
+}
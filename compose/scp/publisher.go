@@ -1,9 +1,14 @@
 package scp
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"math"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/compose-network/specs/compose"
 
@@ -11,16 +16,64 @@ import (
 )
 
 var (
-	ErrDuplicatedVote       = errors.New("duplicated vote")
-	ErrSenderNotParticipant = errors.New("sender is not a participant")
+	ErrNoParticipants       = errors.New("instance has no participants")
+	ErrInstanceIDMismatch   = errors.New("instance ID does not match its contents")
+	ErrNotStarted           = errors.New("instance not started")
+	ErrAlreadyStarted       = errors.New("instance already started")
+	ErrNilRebroadcastCtx    = errors.New("start rebroadcast context is nil")
+	ErrMissingParticipant   = errors.New("participants do not include every request chain")
+	ErrInvalidThreshold     = errors.New("voting threshold is zero or above the participants weight")
+	ErrDuplicatedVote       = compose.NewError(compose.ErrDuplicatedVote, "duplicated vote")
+	ErrSenderNotParticipant = compose.NewError(compose.ErrNotParticipant, "sender is not a participant")
 )
 
 type PublisherInstance interface {
 	Instance() compose.Instance
 	DecisionState() compose.DecisionState
-	Run()
+	DecisionReason() DecisionReason
+	Run() error
 	ProcessVote(sender compose.ChainID, vote bool) error
+	RemoveParticipant(chainID compose.ChainID) error
 	Timeout() error
+	VoteTimings() map[compose.ChainID]time.Time
+	SlowestVoter() (compose.ChainID, time.Duration)
+}
+
+// DecisionReason tells why an instance reached its terminal decision state.
+type DecisionReason int
+
+const (
+	// ReasonNone is the reason of pending instances.
+	ReasonNone DecisionReason = iota
+	// ReasonUnanimousAccept: all participants voted true.
+	ReasonUnanimousAccept
+	// ReasonThresholdAccept: the true votes reached the threshold, with weighted voting.
+	ReasonThresholdAccept
+	// ReasonFalseVote: a participant voted false.
+	ReasonFalseVote
+	// ReasonTimeout: the instance timed out before being decided.
+	ReasonTimeout
+	// ReasonNoParticipants: the instance had no participants to vote.
+	ReasonNoParticipants
+)
+
+func (d DecisionReason) String() string {
+	switch d {
+	case ReasonNone:
+		return "None"
+	case ReasonUnanimousAccept:
+		return "UnanimousAccept"
+	case ReasonThresholdAccept:
+		return "ThresholdAccept"
+	case ReasonFalseVote:
+		return "FalseVote"
+	case ReasonTimeout:
+		return "Timeout"
+	case ReasonNoParticipants:
+		return "NoParticipants"
+	default:
+		return "Unknown"
+	}
 }
 
 type PublisherNetwork interface {
@@ -28,18 +81,119 @@ type PublisherNetwork interface {
 	SendDecided(instanceID compose.InstanceID, decided bool)
 }
 
+// DecisionHook is called once when an instance reaches a terminal state, with a copy of the received votes.
+type DecisionHook func(id compose.InstanceID, state compose.DecisionState, votes map[compose.ChainID]bool)
+
+// PublisherOption configures optional behavior of a publisher instance.
+type PublisherOption func(*publisherInstance)
+
+// WithOnDecision sets a hook fired exactly once when the instance gets decided
+// (accepted, rejected by a false vote, or rejected by timeout).
+// The hook runs outside the instance lock, so it may safely call back into the instance.
+func WithOnDecision(hook DecisionHook) PublisherOption {
+	return func(r *publisherInstance) {
+		r.onDecision = hook
+	}
+}
+
+// WithWeightedVoting replaces the unanimous rule by a weighted one: the instance is accepted as soon as
+// the sum of the weights of the chains that voted true reaches the threshold.
+// Any false vote still rejects the instance immediately. Participants without a weight count as 0.
+// NewPublisherInstance fails with ErrInvalidThreshold if the threshold is zero or above the participants weight.
+func WithWeightedVoting(weights map[compose.ChainID]uint64, threshold uint64) PublisherOption {
+	return func(r *publisherInstance) {
+		r.weights = maps.Clone(weights)
+		r.threshold = threshold
+	}
+}
+
+// WithInstanceVerifier sets a verifier called on the instance before creating the publisher instance
+// (e.g. sbcp.VerifyInstanceID). If it fails, NewPublisherInstance returns ErrInstanceIDMismatch.
+func WithInstanceVerifier(verify func(instance compose.Instance) bool) PublisherOption {
+	return func(r *publisherInstance) {
+		r.verifyInstance = verify
+	}
+}
+
+// WithParticipants overrides the voting set, which is the request's chains by default.
+// It must include every request chain, otherwise NewPublisherInstance returns ErrMissingParticipant,
+// and may add chains that don't submit transactions but whose vote is still required (e.g. a watcher chain).
+func WithParticipants(participants []compose.ChainID) PublisherOption {
+	return func(r *publisherInstance) {
+		r.participants = slices.Compact(slices.Sorted(slices.Values(participants)))
+	}
+}
+
+// WithPublisherClock overrides the clock used to timestamp the instance start and votes,
+// and to schedule StartInstance rebroadcasts (the system one by default).
+func WithPublisherClock(clock Clock) PublisherOption {
+	return func(r *publisherInstance) {
+		r.clock = clock
+	}
+}
+
+// RebroadcastPolicy configures how StartInstance is resent while no participant has voted yet.
+type RebroadcastPolicy struct {
+	// Maximum number of resends after the initial broadcast.
+	MaxRetries int
+	// Delay before the first resend, doubled after each one.
+	Interval time.Duration
+}
+
+// WithStartRebroadcast makes Run resend StartInstance following the policy, in case the transport dropped it,
+// until the first vote arrives, the instance gets decided, or ctx is cancelled.
+// NewPublisherInstance fails with ErrNilRebroadcastCtx if ctx is nil.
+func WithStartRebroadcast(ctx context.Context, policy RebroadcastPolicy) PublisherOption {
+	return func(r *publisherInstance) {
+		r.rebroadcastCtx = ctx
+		r.rebroadcast = &policy
+	}
+}
+
+// decisionEvent holds a decision to be notified once the instance lock is released.
+type decisionEvent struct {
+	state compose.DecisionState
+	votes map[compose.ChainID]bool
+}
+
 type publisherInstance struct {
 	mu sync.Mutex
 
 	// Dependencies
-	network PublisherNetwork
+	network    PublisherNetwork
+	onDecision DecisionHook // optional
 	// SCP instance
 	instance compose.Instance
-	chains   []compose.ChainID
+	// Voting set: the request's chains, unless overridden by participants
+	chains       []compose.ChainID
+	participants []compose.ChainID // optional
+	// Voting weights and acceptance threshold. If weights is nil, all participants must vote true.
+	weights   map[compose.ChainID]uint64
+	threshold uint64
+	// Optional verifier of the instance ID against its contents
+	verifyInstance func(instance compose.Instance) bool
+	// Optional StartInstance rebroadcast policy, stopped by rebroadcastCtx
+	rebroadcast    *RebroadcastPolicy
+	rebroadcastCtx context.Context
+	// Backoff and timer of the next rebroadcast, stopped on the first vote or decision
+	rebroadcastBackoff *compose.Backoff
+	rebroadcastTimer   Timer
+	// Time source of startedAt and voteTimes, and scheduler of rebroadcasts
+	clock Clock
 
 	// Protocol state
+	// Whether Run broadcast the StartInstance. Votes and timeouts are rejected before.
+	started       bool
 	decisionState compose.DecisionState
-	votes         map[compose.ChainID]bool
+	// Why the instance got decided (ReasonNone while pending)
+	decisionReason DecisionReason
+	votes          map[compose.ChainID]bool
+	// Time at which Run broadcast the StartInstance, and at which each vote was received
+	startedAt time.Time
+	voteTimes map[compose.ChainID]time.Time
+
+	// Decision waiting to be notified through onDecision
+	pendingDecisionEvent *decisionEvent
 
 	logger zerolog.Logger
 }
@@ -48,6 +202,7 @@ func NewPublisherInstance(
 	instance compose.Instance,
 	network PublisherNetwork,
 	logger zerolog.Logger,
+	opts ...PublisherOption,
 ) (PublisherInstance, error) {
 	// Build runner
 	r := &publisherInstance{
@@ -57,8 +212,34 @@ func NewPublisherInstance(
 		chains:        instance.Chains(),
 		decisionState: compose.DecisionStatePending,
 		votes:         make(map[compose.ChainID]bool),
+		voteTimes:     make(map[compose.ChainID]time.Time),
+		clock:         systemClock{},
 		logger:        logger,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.rebroadcast != nil && r.rebroadcastCtx == nil {
+		return nil, ErrNilRebroadcastCtx
+	}
+	if r.verifyInstance != nil && !r.verifyInstance(instance) {
+		return nil, fmt.Errorf("instance %s: %w", instance.ID, ErrInstanceIDMismatch)
+	}
+	if r.participants != nil {
+		for _, chainID := range r.chains {
+			if !slices.Contains(r.participants, chainID) {
+				return nil, fmt.Errorf("request chain %d: %w", chainID, ErrMissingParticipant)
+			}
+		}
+		r.chains = r.participants
+	}
+	if r.weights != nil {
+		if total := r.totalWeight(); r.threshold == 0 || total < r.threshold {
+			return nil, fmt.Errorf("threshold %d with participants weight %d: %w",
+				r.threshold, total, ErrInvalidThreshold)
+		}
+	}
 
 	return r, nil
 }
@@ -69,6 +250,13 @@ func (r *publisherInstance) DecisionState() compose.DecisionState {
 	return r.decisionState
 }
 
+// DecisionReason returns why the instance got decided, or ReasonNone while pending.
+func (r *publisherInstance) DecisionReason() DecisionReason {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.decisionReason
+}
+
 func (r *publisherInstance) Instance() compose.Instance {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -77,13 +265,97 @@ func (r *publisherInstance) Instance() compose.Instance {
 
 // Run performs launches the instance by sending a message to all participants.
 // Call this once after creation.
-func (r *publisherInstance) Run() {
+// An instance without participants could never be accepted, so it's rejected right away with ErrNoParticipants.
+// Further calls return ErrAlreadyStarted.
+func (r *publisherInstance) Run() error {
+	r.mu.Lock()
+	if r.started {
+		r.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	r.started = true
+	r.startedAt = r.clock.Now()
+	if len(r.chains) == 0 {
+		r.logger.Warn().
+			Msg("Instance has no participants, rejecting")
+		r.decide(false, ReasonNoParticipants)
+		event := r.takeDecisionEvent()
+		r.mu.Unlock()
+
+		r.notifyDecision(event)
+		return ErrNoParticipants
+	}
+	if r.rebroadcast != nil {
+		r.rebroadcastBackoff = compose.NewBackoff(r.rebroadcast.Interval, 0, 0, 0)
+		r.scheduleRebroadcast(1)
+	}
+	r.mu.Unlock()
+
 	r.network.SendStartInstance(r.instance)
+	return nil
+}
+
+// scheduleRebroadcast schedules the given StartInstance resend, if allowed by the rebroadcast policy.
+func (r *publisherInstance) scheduleRebroadcast(retry int) {
+	// Caller must hold the r mutex
+	if retry > r.rebroadcast.MaxRetries {
+		r.rebroadcastTimer = nil
+		return
+	}
+	r.rebroadcastTimer = r.clock.AfterFunc(r.rebroadcastBackoff.Next(), func() { r.rebroadcastStart(retry) })
+}
+
+// rebroadcastStart resends StartInstance and schedules the next resend,
+// unless the first vote arrived, the instance got decided, or the policy context was cancelled.
+func (r *publisherInstance) rebroadcastStart(retry int) {
+	if r.rebroadcastCtx.Err() != nil {
+		return
+	}
+	r.mu.Lock()
+	if r.decisionState != compose.DecisionStatePending || len(r.votes) > 0 {
+		r.mu.Unlock()
+		return
+	}
+	r.scheduleRebroadcast(retry + 1)
+	r.mu.Unlock()
+
+	r.logger.Debug().
+		Int("retry", retry).
+		Msg("No vote received yet, resending start instance")
+	r.network.SendStartInstance(r.instance)
+}
+
+// stopRebroadcast stops the scheduled StartInstance resend, if any.
+func (r *publisherInstance) stopRebroadcast() {
+	// Caller must hold the r mutex
+	if r.rebroadcastTimer != nil {
+		r.rebroadcastTimer.Stop()
+		r.rebroadcastTimer = nil
+	}
 }
 
+// ProcessVote processes a participant vote.
+// Votes received before Run are rejected with ErrNotStarted, since participants couldn't know the instance yet.
 func (r *publisherInstance) ProcessVote(sender compose.ChainID, vote bool) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	if !r.started {
+		r.mu.Unlock()
+		r.logger.Info().
+			Uint64("chain_id", uint64(sender)).
+			Bool("vote", vote).
+			Msg("Ignoring vote because instance not started")
+		return ErrNotStarted
+	}
+	err := r.processVote(sender, vote)
+	event := r.takeDecisionEvent()
+	r.mu.Unlock()
+
+	r.notifyDecision(event)
+	return err
+}
+
+func (r *publisherInstance) processVote(sender compose.ChainID, vote bool) error {
+	// Caller must hold the r mutex
 
 	if r.decisionState != compose.DecisionStatePending {
 		r.logger.Info().
@@ -110,14 +382,27 @@ func (r *publisherInstance) ProcessVote(sender compose.ChainID, vote bool) error
 	}
 
 	r.votes[sender] = vote
+	r.voteTimes[sender] = r.clock.Now()
+	r.stopRebroadcast()
 
 	// If any vote is false, decide false immediately
 	if !vote {
 		r.logger.Info().
 			Uint64("chain_id", uint64(sender)).
 			Msg("Received reject vote, rejecting instance")
-		r.decisionState = compose.DecisionStateRejected
-		r.network.SendDecided(r.instance.ID, false)
+		r.decide(false, ReasonFalseVote)
+		return nil
+	}
+
+	if r.weights != nil {
+		// Check if the accepting weight reached the threshold
+		if weight := r.acceptingWeight(); weight >= r.threshold {
+			r.logger.Info().
+				Uint64("accepting_weight", weight).
+				Uint64("threshold", r.threshold).
+				Msg("Voting threshold reached, accepting instance")
+			r.decide(true, ReasonThresholdAccept)
+		}
 		return nil
 	}
 
@@ -125,31 +410,174 @@ func (r *publisherInstance) ProcessVote(sender compose.ChainID, vote bool) error
 	if len(r.votes) == len(r.chains) {
 		r.logger.Info().
 			Msg("All votes received, accepting instance")
-		r.decisionState = compose.DecisionStateAccepted
-		r.network.SendDecided(r.instance.ID, true)
+		r.decide(true, ReasonUnanimousAccept)
 		return nil
 	}
 
 	return nil
 }
 
-func (r *publisherInstance) Timeout() error {
+// acceptingWeight returns the sum of the weights of the chains that voted true.
+func (r *publisherInstance) acceptingWeight() uint64 {
+	// Caller must hold the r mutex
+	var weight uint64
+	for chainID, vote := range r.votes {
+		if vote {
+			weight += r.weights[chainID]
+		}
+	}
+	return weight
+}
+
+// totalWeight returns the sum of the weights of all participants, saturating on overflow.
+func (r *publisherInstance) totalWeight() uint64 {
+	var total uint64
+	for _, chainID := range r.chains {
+		weight := r.weights[chainID]
+		if total > math.MaxUint64-weight {
+			return math.MaxUint64
+		}
+		total += weight
+	}
+	return total
+}
+
+// RemoveParticipant removes the chain from the voting set (e.g. because it declined the instance),
+// discarding its vote, or returns ErrSenderNotParticipant if it's not a participant.
+// If no participant remains, a started instance is rejected right away with ErrNoParticipants,
+// as Run does, instead of waiting for its timeout. Without weighted voting, it's accepted if all the remaining
+// participants already voted true. Removals once decided are ignored.
+func (r *publisherInstance) RemoveParticipant(chainID compose.ChainID) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	if r.decisionState != compose.DecisionStatePending {
+		r.mu.Unlock()
+		r.logger.Info().
+			Uint64("chain_id", uint64(chainID)).
+			Msg("Ignoring participant removal because already decided")
+		return nil
+	}
+	if !r.chainInInstance(chainID) {
+		r.mu.Unlock()
+		return fmt.Errorf("chain %d: %w", chainID, ErrSenderNotParticipant)
+	}
+
+	r.chains = slices.DeleteFunc(slices.Clone(r.chains), func(c compose.ChainID) bool { return c == chainID })
+	delete(r.votes, chainID)
+	delete(r.voteTimes, chainID)
+	r.logger.Info().
+		Uint64("chain_id", uint64(chainID)).
+		Int("remaining_participants", len(r.chains)).
+		Msg("Removed participant")
+
+	var err error
+	switch {
+	case !r.started:
+		// Run rejects the instance if no participant remains
+	case len(r.chains) == 0:
+		r.logger.Warn().
+			Msg("No participants remain, rejecting")
+		r.decide(false, ReasonNoParticipants)
+		err = ErrNoParticipants
+	case r.weights == nil && len(r.votes) == len(r.chains):
+		// Only true votes are recorded while pending
+		r.logger.Info().
+			Msg("All remaining votes received, accepting instance")
+		r.decide(true, ReasonUnanimousAccept)
+	}
+	event := r.takeDecisionEvent()
+	r.mu.Unlock()
+
+	r.notifyDecision(event)
+	return err
+}
 
+// Timeout rejects the instance if still pending, or returns ErrNotStarted if called before Run.
+func (r *publisherInstance) Timeout() error {
+	r.mu.Lock()
+	if !r.started {
+		r.mu.Unlock()
+		return ErrNotStarted
+	}
 	if r.decisionState != compose.DecisionStatePending {
 		r.logger.Info().
 			Msg("Ignoring timeout because already decided")
+		r.mu.Unlock()
 		return nil
 	}
 
 	r.logger.Info().
 		Msg("Instance timed out, rejecting")
-	r.decisionState = compose.DecisionStateRejected
-	r.network.SendDecided(r.instance.ID, false)
+	r.decide(false, ReasonTimeout)
+	event := r.takeDecisionEvent()
+	r.mu.Unlock()
+
+	r.notifyDecision(event)
 	return nil
 }
 
+// VoteTimings returns a copy of the time at which each participant vote was received.
+// Ignored votes (duplicated, from non-participants, or received once decided) aren't recorded.
+func (r *publisherInstance) VoteTimings() map[compose.ChainID]time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.voteTimes)
+}
+
+// SlowestVoter returns the participant whose vote was received last, and how long after Run it was received,
+// e.g. to track slow chains. Ties are broken by the lowest chain ID. It returns zero values if no vote was received.
+func (r *publisherInstance) SlowestVoter() (compose.ChainID, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var slowest compose.ChainID
+	var slowestAt time.Time
+	for _, chainID := range slices.Sorted(maps.Keys(r.voteTimes)) {
+		if at := r.voteTimes[chainID]; slowestAt.IsZero() || at.After(slowestAt) {
+			slowest, slowestAt = chainID, at
+		}
+	}
+	if slowestAt.IsZero() {
+		return 0, 0
+	}
+	return slowest, slowestAt.Sub(r.startedAt)
+}
+
+// decide sets the terminal decision state and reason, and sends the decided message to all participants.
+func (r *publisherInstance) decide(accepted bool, reason DecisionReason) {
+	// Caller must hold the r mutex
+	if accepted {
+		r.decisionState = compose.DecisionStateAccepted
+	} else {
+		r.decisionState = compose.DecisionStateRejected
+	}
+	r.decisionReason = reason
+	r.stopRebroadcast()
+	r.network.SendDecided(r.instance.ID, accepted)
+
+	if r.onDecision != nil {
+		r.pendingDecisionEvent = &decisionEvent{
+			state: r.decisionState,
+			votes: maps.Clone(r.votes),
+		}
+	}
+}
+
+// takeDecisionEvent returns and clears the decision waiting to be notified, if any.
+func (r *publisherInstance) takeDecisionEvent() *decisionEvent {
+	// Caller must hold the r mutex
+	event := r.pendingDecisionEvent
+	r.pendingDecisionEvent = nil
+	return event
+}
+
+// notifyDecision fires the decision hook. Must be called without holding the r mutex.
+func (r *publisherInstance) notifyDecision(event *decisionEvent) {
+	if event == nil {
+		return
+	}
+	r.onDecision(r.instance.ID, event.state, event.votes)
+}
+
 func (r *publisherInstance) chainInInstance(chainID compose.ChainID) bool {
 	return slices.Contains(r.chains, chainID)
 }
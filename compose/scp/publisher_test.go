@@ -1,8 +1,10 @@
 package scp
 
 import (
+	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -48,7 +50,7 @@ func TestPublisher_AllTrueVotesDecidesTrue(t *testing.T) {
 
 	// No start until Run is called
 	assert.Equal(t, 0, net.startCalled, "unexpected start before Run")
-	pub.Run()
+	require.NoError(t, pub.Run())
 	assert.Equal(t, 1, net.startCalled)
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
 
@@ -94,7 +96,7 @@ func TestPublisher_NonParticipantVoteErrors(t *testing.T) {
 
 	pub, err := NewPublisherInstance(inst, net, testLogger())
 	require.NoError(t, err)
-	pub.Run()
+	require.NoError(t, pub.Run())
 
 	err = pub.ProcessVote(compose.ChainID(99), true)
 	require.ErrorIs(t, err, ErrSenderNotParticipant)
@@ -123,7 +125,7 @@ func TestPublisher_AnyFalseDecidesFalseEarly(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, inst, pub.Instance())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
-	pub.Run()
+	require.NoError(t, pub.Run())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
 
 	// First false triggers immediate decision
@@ -155,7 +157,7 @@ func TestPublisher_TimeoutDecidesFalse(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, inst, pub.Instance())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
-	pub.Run()
+	require.NoError(t, pub.Run())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
 
 	require.NoError(t, pub.Timeout())
@@ -186,7 +188,7 @@ func TestPublisher_TimeoutAfterTrueDecisionIgnored(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, inst, pub.Instance())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
-	pub.Run()
+	require.NoError(t, pub.Run())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
 
 	// Collect all true votes -> decide(true)
@@ -220,7 +222,7 @@ func TestPublisher_OneVoteThenTimeout_DecidesFalse(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, inst, pub.Instance())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
-	pub.Run()
+	require.NoError(t, pub.Run())
 	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
 
 	// Only one participant votes true; not enough to decide true.
@@ -237,3 +239,505 @@ func TestPublisher_OneVoteThenTimeout_DecidesFalse(t *testing.T) {
 		assert.Equal(t, inst.ID, net.decisions[0].ID)
 	}
 }
+
+func TestPublisher_OnDecision_FiresOncePerTerminalPath(t *testing.T) {
+	newPub := func(t *testing.T) (PublisherInstance, *decisionRecorder) {
+		t.Helper()
+		rec := &decisionRecorder{}
+		inst := compose.Instance{
+			ID: compose.InstanceID{4},
+			XTRequest: compose.XTRequest{
+				Transactions: []compose.TransactionRequest{
+					txReq(1, "a"),
+					txReq(2, "b"),
+				},
+			},
+		}
+		var pub PublisherInstance
+		pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(), WithOnDecision(
+			func(id compose.InstanceID, state compose.DecisionState, votes map[compose.ChainID]bool) {
+				// Calling back into the instance must not deadlock
+				assert.Equal(t, state, pub.DecisionState())
+				rec.hook(id, state, votes)
+			},
+		))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+		return pub, rec
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		pub, rec := newPub(t)
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+		assert.Empty(t, rec.calls)
+		require.NoError(t, pub.ProcessVote(compose.ChainID(2), true))
+		require.NoError(t, pub.Timeout())
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), false))
+
+		require.Len(t, rec.calls, 1)
+		assert.Equal(t, compose.InstanceID{4}, rec.calls[0].ID)
+		assert.Equal(t, compose.DecisionStateAccepted, rec.calls[0].State)
+		assert.Equal(t, map[compose.ChainID]bool{1: true, 2: true}, rec.calls[0].Votes)
+	})
+
+	t.Run("rejected by false vote", func(t *testing.T) {
+		pub, rec := newPub(t)
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), false))
+		require.NoError(t, pub.ProcessVote(compose.ChainID(2), false))
+		require.NoError(t, pub.Timeout())
+
+		require.Len(t, rec.calls, 1)
+		assert.Equal(t, compose.DecisionStateRejected, rec.calls[0].State)
+		assert.Equal(t, map[compose.ChainID]bool{1: false}, rec.calls[0].Votes)
+	})
+
+	t.Run("rejected by timeout", func(t *testing.T) {
+		pub, rec := newPub(t)
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+		require.NoError(t, pub.Timeout())
+		require.NoError(t, pub.Timeout())
+		require.NoError(t, pub.ProcessVote(compose.ChainID(2), true))
+
+		require.Len(t, rec.calls, 1)
+		assert.Equal(t, compose.DecisionStateRejected, rec.calls[0].State)
+		assert.Equal(t, map[compose.ChainID]bool{1: true}, rec.calls[0].Votes)
+	})
+}
+
+func TestPublisher_WeightedVoting(t *testing.T) {
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				txReq(10, "a"),
+				txReq(11, "b"),
+				txReq(12, "c"),
+			},
+		},
+	}
+	weights := map[compose.ChainID]uint64{10: 5, 11: 3, 12: 2}
+
+	t.Run("accepts_when_threshold_reached_by_subset", func(t *testing.T) {
+		net := &fakePublisherNetwork{}
+		pub, err := NewPublisherInstance(inst, net, testLogger(), WithWeightedVoting(weights, 8))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		require.NoError(t, pub.ProcessVote(compose.ChainID(10), true))
+		assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
+
+		require.NoError(t, pub.ProcessVote(compose.ChainID(11), true))
+		assert.Equal(t, compose.DecisionStateAccepted, pub.DecisionState())
+		if assert.Len(t, net.decisions, 1) {
+			assert.True(t, net.decisions[0].Value)
+		}
+
+		// The remaining chain's vote is ignored once decided
+		require.NoError(t, pub.ProcessVote(compose.ChainID(12), false))
+		assert.Equal(t, compose.DecisionStateAccepted, pub.DecisionState())
+		assert.Equal(t, 1, net.decidedCalled)
+	})
+
+	t.Run("false_vote_rejects_immediately", func(t *testing.T) {
+		net := &fakePublisherNetwork{}
+		pub, err := NewPublisherInstance(inst, net, testLogger(), WithWeightedVoting(weights, 8))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		require.NoError(t, pub.ProcessVote(compose.ChainID(12), true))
+		require.NoError(t, pub.ProcessVote(compose.ChainID(10), false))
+		assert.Equal(t, compose.DecisionStateRejected, pub.DecisionState())
+		if assert.Len(t, net.decisions, 1) {
+			assert.False(t, net.decisions[0].Value)
+		}
+	})
+
+	t.Run("unknown_chain_errors", func(t *testing.T) {
+		net := &fakePublisherNetwork{}
+		pub, err := NewPublisherInstance(inst, net, testLogger(), WithWeightedVoting(weights, 8))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		require.ErrorIs(t, pub.ProcessVote(compose.ChainID(99), true), ErrSenderNotParticipant)
+		assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
+	})
+
+	t.Run("invalid_threshold_errors", func(t *testing.T) {
+		for _, threshold := range []uint64{0, 11} {
+			_, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(),
+				WithWeightedVoting(weights, threshold))
+			require.ErrorIs(t, err, ErrInvalidThreshold, "threshold %d", threshold)
+		}
+
+		// The participants weight is the one of the voting set
+		_, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(),
+			WithWeightedVoting(map[compose.ChainID]uint64{10: 5, 99: 5}, 10))
+		require.ErrorIs(t, err, ErrInvalidThreshold)
+	})
+
+	t.Run("threshold_of_total_weight_needs_every_vote", func(t *testing.T) {
+		pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(), WithWeightedVoting(weights, 10))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		for _, chainID := range []compose.ChainID{10, 11} {
+			require.NoError(t, pub.ProcessVote(chainID, true))
+			assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
+		}
+		require.NoError(t, pub.ProcessVote(compose.ChainID(12), true))
+		assert.Equal(t, compose.DecisionStateAccepted, pub.DecisionState())
+		assert.Equal(t, ReasonThresholdAccept, pub.DecisionReason())
+	})
+}
+
+func TestPublisher_NoParticipantsRejectsImmediately(t *testing.T) {
+	net := &fakePublisherNetwork{}
+	decisions := &decisionRecorder{}
+	inst := compose.Instance{ID: compose.InstanceID{1}}
+	pub, err := NewPublisherInstance(inst, net, testLogger(), WithOnDecision(decisions.hook))
+	require.NoError(t, err)
+
+	require.ErrorIs(t, pub.Run(), ErrNoParticipants)
+	assert.Equal(t, compose.DecisionStateRejected, pub.DecisionState())
+	assert.Equal(t, 0, net.startCalled)
+	if assert.Len(t, net.decisions, 1) {
+		assert.False(t, net.decisions[0].Value)
+		assert.Equal(t, inst.ID, net.decisions[0].ID)
+	}
+	assert.Len(t, decisions.calls, 1)
+
+	// Later timeouts don't decide again
+	require.NoError(t, pub.Timeout())
+	assert.Equal(t, 1, net.decidedCalled)
+}
+
+func TestPublisher_RemoveParticipant(t *testing.T) {
+	inst := compose.Instance{
+		ID: compose.InstanceID{1},
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{txReq(1, "a"), txReq(2, "b")},
+		},
+	}
+
+	t.Run("removing_all_rejects_immediately", func(t *testing.T) {
+		net := &fakePublisherNetwork{}
+		decisions := &decisionRecorder{}
+		pub, err := NewPublisherInstance(inst, net, testLogger(), WithOnDecision(decisions.hook))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+
+		require.NoError(t, pub.RemoveParticipant(compose.ChainID(1)))
+		assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
+		require.ErrorIs(t, pub.RemoveParticipant(compose.ChainID(2)), ErrNoParticipants)
+		assert.Equal(t, compose.DecisionStateRejected, pub.DecisionState())
+		assert.Equal(t, ReasonNoParticipants, pub.DecisionReason())
+		if assert.Len(t, net.decisions, 1) {
+			assert.False(t, net.decisions[0].Value)
+		}
+		assert.Len(t, decisions.calls, 1)
+
+		// Later timeouts don't decide again
+		require.NoError(t, pub.Timeout())
+		assert.Equal(t, 1, net.decidedCalled)
+	})
+
+	t.Run("remaining_votes_accept", func(t *testing.T) {
+		net := &fakePublisherNetwork{}
+		pub, err := NewPublisherInstance(inst, net, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+
+		require.NoError(t, pub.RemoveParticipant(compose.ChainID(2)))
+		assert.Equal(t, compose.DecisionStateAccepted, pub.DecisionState())
+		assert.Equal(t, ReasonUnanimousAccept, pub.DecisionReason())
+	})
+
+	t.Run("removed_before_run", func(t *testing.T) {
+		net := &fakePublisherNetwork{}
+		pub, err := NewPublisherInstance(inst, net, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, pub.RemoveParticipant(compose.ChainID(1)))
+		require.NoError(t, pub.RemoveParticipant(compose.ChainID(2)))
+
+		require.ErrorIs(t, pub.Run(), ErrNoParticipants)
+		assert.Equal(t, compose.DecisionStateRejected, pub.DecisionState())
+		assert.Equal(t, 0, net.startCalled)
+	})
+
+	t.Run("non_participant_errors", func(t *testing.T) {
+		pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		require.ErrorIs(t, pub.RemoveParticipant(compose.ChainID(9)), ErrSenderNotParticipant)
+		assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
+	})
+}
+
+func TestPublisher_DecisionReason(t *testing.T) {
+	inst := compose.Instance{
+		ID: compose.InstanceID{1},
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{txReq(1, "a"), txReq(2, "b")},
+		},
+	}
+	newRunningPublisher := func(t *testing.T) PublisherInstance {
+		pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+		assert.Equal(t, ReasonNone, pub.DecisionReason())
+		return pub
+	}
+
+	t.Run("unanimous_accept", func(t *testing.T) {
+		pub := newRunningPublisher(t)
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+		assert.Equal(t, ReasonNone, pub.DecisionReason())
+		require.NoError(t, pub.ProcessVote(compose.ChainID(2), true))
+		assert.Equal(t, ReasonUnanimousAccept, pub.DecisionReason())
+	})
+
+	t.Run("false_vote", func(t *testing.T) {
+		pub := newRunningPublisher(t)
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+		require.NoError(t, pub.ProcessVote(compose.ChainID(2), false))
+		assert.Equal(t, ReasonFalseVote, pub.DecisionReason())
+		assert.Equal(t, compose.DecisionStateRejected, pub.DecisionState())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		pub := newRunningPublisher(t)
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+		require.NoError(t, pub.Timeout())
+		assert.Equal(t, ReasonTimeout, pub.DecisionReason())
+		assert.Equal(t, compose.DecisionStateRejected, pub.DecisionState())
+
+		// Later events don't change the reason
+		require.NoError(t, pub.ProcessVote(compose.ChainID(2), false))
+		assert.Equal(t, ReasonTimeout, pub.DecisionReason())
+		assert.Equal(t, "Timeout", pub.DecisionReason().String())
+	})
+}
+
+func TestPublisher_VoteTimings(t *testing.T) {
+	inst := compose.Instance{
+		ID: compose.InstanceID{1},
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{txReq(1, "a"), txReq(2, "b"), txReq(3, "c")},
+		},
+	}
+	clock := &fakeClock{now: time.Unix(100, 0)}
+	pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(), WithPublisherClock(clock))
+	require.NoError(t, err)
+	require.NoError(t, pub.Run())
+
+	chainID, latency := pub.SlowestVoter()
+	assert.Equal(t, compose.ChainID(0), chainID)
+	assert.Zero(t, latency)
+
+	clock.Advance(time.Second)
+	require.NoError(t, pub.ProcessVote(compose.ChainID(2), true))
+	clock.Advance(2 * time.Second)
+	require.NoError(t, pub.ProcessVote(compose.ChainID(3), true))
+	// Duplicated votes don't update the timings
+	clock.Advance(time.Second)
+	require.ErrorIs(t, pub.ProcessVote(compose.ChainID(2), true), ErrDuplicatedVote)
+	require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+	require.Equal(t, compose.DecisionStateAccepted, pub.DecisionState())
+
+	timings := pub.VoteTimings()
+	assert.Equal(t, map[compose.ChainID]time.Time{
+		1: time.Unix(104, 0),
+		2: time.Unix(101, 0),
+		3: time.Unix(103, 0),
+	}, timings)
+
+	chainID, latency = pub.SlowestVoter()
+	assert.Equal(t, compose.ChainID(1), chainID)
+	assert.Equal(t, 4*time.Second, latency)
+
+	// The returned timings are a copy
+	delete(timings, 1)
+	assert.Len(t, pub.VoteTimings(), 3)
+}
+
+func TestPublisher_VoteBeforeRunIsRejected(t *testing.T) {
+	net := &fakePublisherNetwork{}
+	inst := compose.Instance{
+		ID: compose.InstanceID{1},
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: compose.ChainID(1), Transactions: [][]byte{{1}}},
+			},
+		},
+	}
+	pub, err := NewPublisherInstance(inst, net, testLogger())
+	require.NoError(t, err)
+
+	require.ErrorIs(t, pub.ProcessVote(compose.ChainID(1), true), ErrNotStarted)
+	require.ErrorIs(t, pub.Timeout(), ErrNotStarted)
+	assert.Equal(t, compose.DecisionStatePending, pub.DecisionState())
+	assert.Equal(t, 0, net.decidedCalled)
+
+	// The rejected vote isn't recorded, so it can be sent again once started
+	require.NoError(t, pub.Run())
+	require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+	assert.Equal(t, compose.DecisionStateAccepted, pub.DecisionState())
+}
+
+func TestPublisher_InstanceVerifier(t *testing.T) {
+	inst := compose.Instance{ID: compose.InstanceID{1}}
+	var verified []compose.InstanceID
+	verifier := func(valid bool) func(compose.Instance) bool {
+		return func(instance compose.Instance) bool {
+			verified = append(verified, instance.ID)
+			return valid
+		}
+	}
+
+	pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(), WithInstanceVerifier(verifier(true)))
+	require.NoError(t, err)
+	assert.NotNil(t, pub)
+
+	pub, err = NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(), WithInstanceVerifier(verifier(false)))
+	require.ErrorIs(t, err, ErrInstanceIDMismatch)
+	assert.Nil(t, pub)
+	assert.Equal(t, []compose.InstanceID{inst.ID, inst.ID}, verified)
+}
+
+func TestPublisher_Participants(t *testing.T) {
+	inst := compose.Instance{
+		ID: compose.InstanceID{1},
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{txReq(1, "a"), txReq(2, "b")},
+		},
+	}
+	const watcher = compose.ChainID(9)
+
+	t.Run("watcher_vote_required", func(t *testing.T) {
+		net := &fakePublisherNetwork{}
+		pub, err := NewPublisherInstance(inst, net, testLogger(), WithParticipants([]compose.ChainID{watcher, 2, 1}))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		require.NoError(t, pub.ProcessVote(1, true))
+		require.NoError(t, pub.ProcessVote(2, true))
+		assert.Equal(t, compose.DecisionStatePending, pub.DecisionState(), "watcher vote still missing")
+
+		require.NoError(t, pub.ProcessVote(watcher, true))
+		assert.Equal(t, compose.DecisionStateAccepted, pub.DecisionState())
+		assert.Equal(t, 1, net.decidedCalled)
+	})
+
+	t.Run("watcher_rejects", func(t *testing.T) {
+		pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(),
+			WithParticipants([]compose.ChainID{1, 2, watcher}))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		require.NoError(t, pub.ProcessVote(watcher, false))
+		assert.Equal(t, compose.DecisionStateRejected, pub.DecisionState())
+	})
+
+	t.Run("not_superset", func(t *testing.T) {
+		pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(),
+			WithParticipants([]compose.ChainID{1, watcher}))
+		require.ErrorIs(t, err, ErrMissingParticipant)
+		assert.Nil(t, pub)
+	})
+
+	t.Run("non_participant", func(t *testing.T) {
+		pub, err := NewPublisherInstance(inst, &fakePublisherNetwork{}, testLogger(),
+			WithParticipants([]compose.ChainID{1, 2}))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+
+		require.ErrorIs(t, pub.ProcessVote(watcher, true), ErrSenderNotParticipant)
+	})
+}
+
+func TestPublisher_StartRebroadcast(t *testing.T) {
+	inst := compose.Instance{
+		ID: compose.InstanceID{1},
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{txReq(1, "a"), txReq(2, "b")},
+		},
+	}
+
+	interval := 10 * time.Millisecond
+	newRebroadcastingPublisher := func(
+		t *testing.T,
+		ctx context.Context,
+		maxRetries int,
+	) (PublisherInstance, *countingPublisherNetwork, *fakeClock) {
+		net := &countingPublisherNetwork{}
+		clock := &fakeClock{now: time.Unix(100, 0)}
+		policy := RebroadcastPolicy{MaxRetries: maxRetries, Interval: interval}
+		pub, err := NewPublisherInstance(inst, net, testLogger(),
+			WithStartRebroadcast(ctx, policy), WithPublisherClock(clock))
+		require.NoError(t, err)
+		require.NoError(t, pub.Run())
+		return pub, net, clock
+	}
+
+	t.Run("stops_after_first_vote", func(t *testing.T) {
+		pub, net, clock := newRebroadcastingPublisher(t, t.Context(), 50)
+
+		// The delay doubles after each resend
+		clock.Advance(interval)
+		assert.Equal(t, 2, net.starts())
+		clock.Advance(interval)
+		assert.Equal(t, 2, net.starts())
+		clock.Advance(interval)
+		assert.Equal(t, 3, net.starts())
+
+		require.NoError(t, pub.ProcessVote(compose.ChainID(1), true))
+		clock.Advance(time.Hour)
+		assert.Equal(t, 3, net.starts())
+	})
+
+	t.Run("bounded_by_max_retries", func(t *testing.T) {
+		_, net, clock := newRebroadcastingPublisher(t, t.Context(), 2)
+
+		for range 5 {
+			clock.Advance(time.Hour)
+		}
+		assert.Equal(t, 3, net.starts())
+	})
+
+	t.Run("stops_on_timeout", func(t *testing.T) {
+		pub, net, clock := newRebroadcastingPublisher(t, t.Context(), 5)
+
+		require.NoError(t, pub.Timeout())
+		for _, timer := range clock.timers {
+			assert.True(t, timer.stopped)
+		}
+		clock.Advance(time.Hour)
+		assert.Equal(t, 1, net.starts())
+	})
+
+	t.Run("rejects_nil_context", func(t *testing.T) {
+		//nolint:staticcheck // a nil context is what's being tested
+		_, err := NewPublisherInstance(inst, &countingPublisherNetwork{}, testLogger(),
+			WithStartRebroadcast(nil, RebroadcastPolicy{MaxRetries: 1, Interval: interval}))
+		require.ErrorIs(t, err, ErrNilRebroadcastCtx)
+	})
+
+	t.Run("second_run_errors", func(t *testing.T) {
+		pub, net, clock := newRebroadcastingPublisher(t, t.Context(), 1)
+
+		require.ErrorIs(t, pub.Run(), ErrAlreadyStarted)
+		clock.Advance(time.Hour)
+		assert.Equal(t, 2, net.starts())
+	})
+
+	t.Run("stops_on_context_cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, net, clock := newRebroadcastingPublisher(t, ctx, 5)
+
+		clock.Advance(time.Hour)
+		assert.Equal(t, 1, net.starts())
+	})
+}
@@ -0,0 +1,74 @@
+package scp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/compose-network/specs/compose"
+)
+
+var ErrUnknownSession = errors.New("no sequencer instance registered for the session")
+
+// Router dispatches incoming mailbox messages to the sequencer instance of their session,
+// for nodes running multiple SCP instances concurrently. It's safe for concurrent use.
+type Router struct {
+	mu        sync.RWMutex
+	instances map[compose.SessionID]SequencerInstance
+	// Chains whose messages are dispatched (all by default)
+	chainFilter compose.ChainFilter
+}
+
+// RouterOption configures optional behavior of the router.
+type RouterOption func(*Router)
+
+// WithRouterChainFilter restricts the chains whose messages are dispatched.
+// Messages from chains not permitted are rejected with compose.ErrChainNotPermitted.
+func WithRouterChainFilter(filter compose.ChainFilter) RouterOption {
+	return func(r *Router) {
+		r.chainFilter = filter
+	}
+}
+
+func NewRouter(opts ...RouterOption) *Router {
+	r := &Router{
+		instances: make(map[compose.SessionID]SequencerInstance),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Register routes the messages of the session to the instance, replacing any previously registered one.
+func (r *Router) Register(sessionID compose.SessionID, instance SequencerInstance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instances[sessionID] = instance
+}
+
+// Deregister stops routing the messages of the session (e.g. once its instance is done).
+func (r *Router) Deregister(sessionID compose.SessionID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.instances, sessionID)
+}
+
+// Dispatch calls ProcessMailboxMessage on the instance registered for the message session,
+// returning its error, or ErrUnknownSession if there is none.
+// Messages from chains not permitted by the chain filter are rejected with compose.ErrChainNotPermitted.
+func (r *Router) Dispatch(msg MailboxMessage) error {
+	if !r.chainFilter.IsPermitted(msg.SourceChainID) {
+		return fmt.Errorf("source chain %d: %w", msg.SourceChainID, compose.ErrChainNotPermitted)
+	}
+
+	r.mu.RLock()
+	instance, ok := r.instances[msg.SessionID]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("session %d: %w", msg.SessionID, ErrUnknownSession)
+	}
+
+	// The instance is called outside the router lock, so that slow instances don't block the others.
+	return instance.ProcessMailboxMessage(msg)
+}
@@ -0,0 +1,66 @@
+package scp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/compose-network/specs/compose"
+)
+
+func TestRouter_Dispatch(t *testing.T) {
+	newWaitingSequencer := func(t *testing.T, need MailboxMessage) (SequencerInstance, *fakeSequencerNetwork) {
+		eng := &fakeExecutionEngine{
+			id:    1,
+			steps: []simulateResp{{read: &need.MailboxMessageHeader}},
+		}
+		net := &fakeSequencerNetwork{}
+		inst := compose.Instance{
+			XTRequest: compose.XTRequest{
+				Transactions: []compose.TransactionRequest{
+					{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+					{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+				},
+			},
+		}
+		seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, seq.Run())
+		return seq, net
+	}
+
+	need1 := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	need2 := makeMsg(compose.ChainID(2), "X", []byte("d2"))
+	need2.SessionID = compose.SessionID(2)
+	seq1, net1 := newWaitingSequencer(t, need1)
+	seq2, net2 := newWaitingSequencer(t, need2)
+
+	router := NewRouter()
+	router.Register(compose.SessionID(1), seq1)
+	router.Register(compose.SessionID(2), seq2)
+
+	require.NoError(t, router.Dispatch(need2))
+	assert.Empty(t, net1.votes)
+	assert.Equal(t, []bool{true}, net2.votes)
+
+	require.NoError(t, router.Dispatch(need1))
+	assert.Equal(t, []bool{true}, net1.votes)
+
+	// Unknown and deregistered sessions
+	unknown := makeMsg(compose.ChainID(2), "X", []byte("d3"))
+	unknown.SessionID = compose.SessionID(3)
+	require.ErrorIs(t, router.Dispatch(unknown), ErrUnknownSession)
+
+	router.Deregister(compose.SessionID(1))
+	require.ErrorIs(t, router.Dispatch(need1), ErrUnknownSession)
+
+	t.Run("chain_filter", func(t *testing.T) {
+		seq, net := newWaitingSequencer(t, need1)
+		filtered := NewRouter(WithRouterChainFilter(compose.NewChainFilter([]compose.ChainID{3}, nil)))
+		filtered.Register(compose.SessionID(1), seq)
+
+		require.ErrorIs(t, filtered.Dispatch(need1), compose.ErrChainNotPermitted)
+		assert.Empty(t, net.votes)
+	})
+}
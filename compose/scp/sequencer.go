@@ -3,7 +3,9 @@ package scp
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -11,17 +13,32 @@ import (
 )
 
 var (
-	ErrNoTransactions       = errors.New("no transactions to execute")
+	ErrNoTransactions       = compose.NewError(compose.ErrNoTransactions, "no transactions to execute")
 	ErrNotInSimulatingState = errors.New("sequencer not in simulating state")
+	ErrEmptyMailboxData     = errors.New("mailbox message fulfilling a read has no data")
+	ErrUnfulfillableRead    = compose.NewError(
+		compose.ErrNotParticipant,
+		"read from a chain that does not participate in the instance",
+	)
 )
 
 // SequencerInstance is an interface that represents the sequencer-side logic for an SCP instance.
 type SequencerInstance interface {
 	DecisionState() compose.DecisionState
 	Run() error
+	DryRun() (DryRunResult, error)
 	ProcessMailboxMessage(msg MailboxMessage) error
 	ProcessDecidedMessage(decided bool) error
 	Timeout()
+	SnapshotState() SequencerSnapshot
+	HasSentWrites() bool
+	SentWriteCount() int
+	WrittenMessages() []MailboxMessage
+	WriteOrigins() map[string]int
+	WaitingLabels() []string
+	State() SequencerState
+	PendingReads() []MailboxMessageHeader
+	Deadline() (time.Time, bool)
 }
 
 // SequencerState tracks the state machine for a sequencer in an SCP session.
@@ -52,26 +69,200 @@ type ExecutionEngine interface {
 	Simulate(request SimulationRequest) (readRequest *MailboxMessageHeader, writeMessages []MailboxMessage, err error)
 }
 
+// OriginTrackingEngine is an ExecutionEngine that can also report which transaction produced each write message.
+// Engines not implementing it are simulated through Simulate, and their write origins are unknown (-1).
+type OriginTrackingEngine interface {
+	ExecutionEngine
+	// SimulateWithOrigins behaves as Simulate, additionally returning, for each write message,
+	// the index in request.Transactions of the transaction that produced it (-1 if unknown).
+	SimulateWithOrigins(request SimulationRequest) (
+		readRequest *MailboxMessageHeader,
+		writeMessages []MailboxMessage,
+		writeOrigins []int,
+		err error,
+	)
+}
+
+// ReadHintingEngine is an ExecutionEngine that can also tell, upon a read miss, which reads the simulation
+// will need afterwards. Expected reads are then fulfilled in the needed order: a read is only included once
+// every read needed before it was received, and all of the available ones are included before re-simulating.
+// Engines not implementing it, or giving no hints, get their expected reads fulfilled as they arrive, in any order.
+type ReadHintingEngine interface {
+	ExecutionEngine
+	// ReadHints returns the reads needed after the last simulated read miss, in the order they'll be needed.
+	ReadHints() []MailboxMessageHeader
+}
+
 type SequencerNetwork interface {
 	SendMailboxMessage(recipient compose.ChainID, msg MailboxMessage)
 	SendVote(vote bool)
 }
 
+// MailboxRequester proactively asks the source chain for a mailbox message that hasn't been received yet.
+type MailboxRequester interface {
+	RequestMailbox(header MailboxMessageHeader)
+}
+
+// Clock is the time source of the sequencer auto-timeout (see WithAutoTimeout), injectable for tests.
+// AfterFunc must run f in its own goroutine (as time.AfterFunc does), never within the AfterFunc call.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer started by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the timer from firing, returning false if it already fired or was stopped.
+	Stop() bool
+}
+
+// systemClock is the Clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// ContradictoryDecisionHook is called when the decided message received from the publisher
+// contradicts the vote sent by this sequencer.
+type ContradictoryDecisionHook func(localVote bool, decided bool)
+
+// DroppedMailboxHook is called with each mailbox message ignored because the instance is no longer simulating.
+type DroppedMailboxHook func(msg MailboxMessage)
+
+// SequencerOption configures optional behavior of a sequencer instance.
+type SequencerOption func(*sequencerInstance)
+
+// WithMailboxRequester sets a requester that is called on every read miss, so that the source chain is
+// proactively asked for the missing message. Without it, the sequencer passively waits for the message.
+func WithMailboxRequester(requester MailboxRequester) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.mailboxRequester = requester
+	}
+}
+
+// WithOnContradictoryDecision sets a hook fired, outside the instance lock, when the first decided message
+// contradicts the local vote (e.g. voted true but the instance was rejected), flagging a potential safety issue.
+func WithOnContradictoryDecision(hook ContradictoryDecisionHook) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.onContradictoryDecision = hook
+	}
+}
+
+// WithOnDroppedMailbox sets a hook fired, outside the instance lock, with every mailbox message ignored because
+// the instance already moved past simulation (e.g. received after voting), to help debug cross-chain ordering.
+func WithOnDroppedMailbox(hook DroppedMailboxHook) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.onDroppedMailbox = hook
+	}
+}
+
+// WithDeadline sets the time by which the instance is expected to have voted.
+// It's informative only (see FindStuck): the Timeout call is still driven by the upper layer.
+func WithDeadline(deadline time.Time) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.deadline = deadline
+	}
+}
+
+// WithAutoTimeout makes the instance time out by itself (as if Timeout was called) if it hasn't voted
+// within the given duration since its creation. It also sets the WithDeadline deadline, if unset.
+// By default, timeouts are driven by the upper layer through Timeout.
+func WithAutoTimeout(timeout time.Duration) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.autoTimeout = timeout
+	}
+}
+
+// WithClock overrides the clock driving WithAutoTimeout (the system clock by default), e.g. with a fake one in tests.
+func WithClock(clock Clock) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.clock = clock
+	}
+}
+
+// WithAllowEmptyMailboxData accepts received mailbox messages with empty data for the given labels,
+// or for any label if none is given. By default, messages fulfilling reads must carry data,
+// while written messages may be empty placeholders.
+func WithAllowEmptyMailboxData(labels ...string) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.allowEmptyData = len(labels) == 0
+		r.emptyDataLabels = make(map[string]struct{}, len(labels))
+		for _, label := range labels {
+			r.emptyDataLabels[label] = struct{}{}
+		}
+	}
+}
+
+// WithMaxPendingMessages caps the number of received mailbox messages buffered while not matching any expected read,
+// so that a peer flooding unexpected messages can't grow memory unbounded. Once over the cap, the oldest
+// buffered messages not matching an expected read are evicted first (if later needed, they're requested again
+// on the read miss through the MailboxRequester, if any). 0, the default, means no cap.
+func WithMaxPendingMessages(maxPending int) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.maxPendingMessages = maxPending
+	}
+}
+
+// WriteDedup tells when two mailbox write messages are the same, so that the second one isn't sent.
+type WriteDedup int
+
+const (
+	// DedupByMessage considers writes the same if both their headers and data are equal.
+	DedupByMessage WriteDedup = iota
+	// DedupByHeader considers writes the same if their headers are equal, for protocols where the header
+	// uniquely identifies a logical message: a retried write with different data isn't sent again.
+	DedupByHeader
+)
+
+// WithWriteDedup sets how repeated mailbox write messages are detected (DedupByMessage by default).
+func WithWriteDedup(dedup WriteDedup) SequencerOption {
+	return func(r *sequencerInstance) {
+		r.writeDedup = dedup
+	}
+}
+
 type sequencerInstance struct {
 	mu sync.Mutex
 
 	// Dependencies
-	execution ExecutionEngine
-	network   SequencerNetwork
+	execution        ExecutionEngine
+	network          SequencerNetwork
+	mailboxRequester MailboxRequester // optional
+	// Optional hook for decisions contradicting the local vote
+	onContradictoryDecision ContradictoryDecisionHook
+	// Optional hook for mailbox messages received after simulation
+	onDroppedMailbox DroppedMailboxHook
+	// Expected voting deadline (zero if unset)
+	deadline time.Time
+	// Optional self-timeout, driven by clock through timeoutTimer
+	autoTimeout  time.Duration
+	clock        Clock
+	timeoutTimer Timer
+	// Whether received mailbox messages may have empty data, for any label or only for emptyDataLabels
+	allowEmptyData  bool
+	emptyDataLabels map[string]struct{}
+	// Maximum number of buffered pendingMessages (0 if unbounded)
+	maxPendingMessages int
+	// How repeated write messages are detected
+	writeDedup WriteDedup
 
 	// Protocol state
 	state         SequencerState
 	decisionState compose.DecisionState
+	// Vote sent to the publisher (nil if not voted yet)
+	localVote *bool
+	// Whether a decided message was received
+	receivedDecided bool
 
 	// List of transactions to be executed by this chain (from the request)
 	txs [][]byte
+	// Chains participating in the instance. Reads from other chains can never be fulfilled.
+	participants []compose.ChainID
 	// Read requests made by the transactions (returned by simulations). Removed on fulfillment.
 	expectedReadRequests []MailboxMessageHeader
+	// Whether expectedReadRequests come from read hints, and so must be fulfilled in order.
+	orderedReads bool
 	// Incoming mailbox messages that can be used to satisfy expected reads.
 	pendingMessages []MailboxMessage
 	// Consumed pendingMessages that should populate the Mailbox contract
@@ -81,6 +272,8 @@ type sequencerInstance struct {
 	vmSnapshot compose.StateRoot
 
 	writtenMessagesCache []MailboxMessage
+	// Index of the transaction that produced each writtenMessagesCache entry (-1 if unknown)
+	writtenOrigins []int
 
 	logger zerolog.Logger
 }
@@ -91,6 +284,7 @@ func NewSequencerInstance(
 	network SequencerNetwork,
 	vmSnapshot compose.StateRoot,
 	logger zerolog.Logger,
+	opts ...SequencerOption,
 ) (SequencerInstance, error) {
 	// Build runner
 	r := &sequencerInstance{
@@ -100,13 +294,19 @@ func NewSequencerInstance(
 		state:                SeqStateSimulating, // First state
 		decisionState:        compose.DecisionStatePending,
 		txs:                  make([][]byte, 0),
+		participants:         compose.ChainsFromRequest(instance.XTRequest),
 		putInboxMessages:     make([]MailboxMessage, 0),
 		expectedReadRequests: make([]MailboxMessageHeader, 0),
 		pendingMessages:      make([]MailboxMessage, 0),
 		vmSnapshot:           vmSnapshot,
 		writtenMessagesCache: make([]MailboxMessage, 0),
+		writtenOrigins:       make([]int, 0),
+		clock:                systemClock{},
 		logger:               logger,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
 
 	// Filter transactions to this chain
 	for _, req := range instance.XTRequest.Transactions {
@@ -120,16 +320,99 @@ func NewSequencerInstance(
 	if len(r.txs) == 0 {
 		return nil, ErrNoTransactions
 	}
+	r.startAutoTimeout()
 
 	return r, nil
 }
 
+// startAutoTimeout starts the WithAutoTimeout timer, if set and the instance is still simulating.
+func (r *sequencerInstance) startAutoTimeout() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.autoTimeout <= 0 || r.state != SeqStateSimulating {
+		return
+	}
+	if r.deadline.IsZero() {
+		r.deadline = r.clock.Now().Add(r.autoTimeout)
+	}
+	r.timeoutTimer = r.clock.AfterFunc(r.autoTimeout, r.Timeout)
+}
+
 func (r *sequencerInstance) DecisionState() compose.DecisionState {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	return r.decisionState
 }
 
+// HasSentWrites returns whether any mailbox write message was already sent by this instance.
+func (r *sequencerInstance) HasSentWrites() bool {
+	return r.SentWriteCount() > 0
+}
+
+// SentWriteCount returns the number of distinct mailbox write messages sent by this instance.
+func (r *sequencerInstance) SentWriteCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.writtenMessagesCache)
+}
+
+// WrittenMessages returns a deep copy of the distinct mailbox write messages sent by this instance, in sending order.
+func (r *sequencerInstance) WrittenMessages() []MailboxMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return cloneMailboxMessages(r.writtenMessagesCache)
+}
+
+// WriteOrigins returns, for each distinct mailbox write message sent (keyed by its header Key),
+// the index of the transaction that produced it, or -1 if the execution engine doesn't report it.
+func (r *sequencerInstance) WriteOrigins() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	origins := make(map[string]int, len(r.writtenMessagesCache))
+	for i, msg := range r.writtenMessagesCache {
+		key := msg.MailboxMessageHeader.Key()
+		if _, ok := origins[key]; !ok {
+			origins[key] = r.writtenOrigins[i]
+		}
+	}
+	return origins
+}
+
+// WaitingLabels returns the deduplicated and sorted labels of the mailbox reads the instance is waiting for.
+func (r *sequencerInstance) WaitingLabels() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	labels := make([]string, 0, len(r.expectedReadRequests))
+	for _, req := range r.expectedReadRequests {
+		labels = append(labels, req.Label)
+	}
+	slices.Sort(labels)
+	return slices.Compact(labels)
+}
+
+// State returns the current state of the sequencer state machine.
+func (r *sequencerInstance) State() SequencerState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// PendingReads returns a copy of the mailbox reads the instance is still waiting for.
+func (r *sequencerInstance) PendingReads() []MailboxMessageHeader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.expectedReadRequests)
+}
+
+// Deadline returns the expected voting deadline, if set through WithDeadline.
+func (r *sequencerInstance) Deadline() (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deadline, !r.deadline.IsZero()
+}
+
 // Run executes calls to the mailbox-aware simulation.
 // If simulation succeeds, it sends Vote(true) to the SP and set state to waiting for decided.
 // If simulation fails due to read miss, it adds the expected read message and looks for new reads to insert.
@@ -142,7 +425,7 @@ func (r *sequencerInstance) Run() error {
 	}
 
 	// Run simulation
-	readRequest, writeMessages, err := r.execution.Simulate(SimulationRequest{
+	readRequest, writeMessages, writeOrigins, err := r.simulate(SimulationRequest{
 		PutInboxMessages: append([]MailboxMessage(nil), r.putInboxMessages...),
 		Transactions:     compose.CloneByteSlices(r.txs),
 		Snapshot:         r.vmSnapshot,
@@ -150,7 +433,7 @@ func (r *sequencerInstance) Run() error {
 	if err != nil {
 		r.logger.Info().Msg("Simulation failed, rejecting instance. Error: " + err.Error())
 
-		r.network.SendVote(false)
+		r.sendVote(false)
 		r.state = SeqStateDone
 		r.decisionState = compose.DecisionStateRejected
 		r.mu.Unlock()
@@ -159,33 +442,70 @@ func (r *sequencerInstance) Run() error {
 	}
 
 	// Send write messages
-	r.sendWriteMessages(writeMessages)
+	r.sendWriteMessages(writeMessages, writeOrigins)
 
 	// Consume mailbox messages.
 	if readRequest != nil {
+		reads, ordered, err := r.expectReads(r.expectedReadRequests, *readRequest)
+		if err != nil {
+			r.logger.Info().
+				Err(err).
+				Uint64("source_chain_id", uint64(readRequest.SourceChainID)).
+				Str("label", readRequest.Label).
+				Msg("Simulation needs a read from a non-participant chain, rejecting instance.")
+
+			r.sendVote(false)
+			r.state = SeqStateDone
+			r.decisionState = compose.DecisionStateRejected
+			r.mu.Unlock()
+
+			return err
+		}
 		r.logger.Info().
 			Uint64("source_chain_id", uint64(readRequest.SourceChainID)).
 			Str("label", readRequest.Label).
 			Msg("Simulation hit read miss, requesting mailbox message.")
-		r.expectedReadRequests = append(r.expectedReadRequests, *readRequest)
+		r.expectedReadRequests, r.orderedReads = reads, ordered
+		requestMailbox := r.mailboxRequester != nil && !r.hasPendingMessage(*readRequest)
 		r.mu.Unlock()
+
+		// The requester may deliver the message right away, through ProcessMailboxMessage
+		if requestMailbox {
+			r.mailboxRequester.RequestMailbox(*readRequest)
+		}
 		return r.consumeReceivedMailboxMessagesAndSimulate()
 	}
 
 	// Vote true.
 	r.logger.Info().Msg("Simulation succeeded, voting true.")
-	r.network.SendVote(true)
+	r.sendVote(true)
 	r.state = SeqStateWaitingDecided
 	r.mu.Unlock()
 	return nil
 }
 
-func (r *sequencerInstance) sendWriteMessages(messages []MailboxMessage) {
-	for _, msg := range messages {
+// simulate runs the simulation, along with the write origins if the engine reports them.
+// Write origins are -1 for unknown origins.
+func (r *sequencerInstance) simulate(request SimulationRequest) (
+	*MailboxMessageHeader,
+	[]MailboxMessage,
+	[]int,
+	error,
+) {
+	// Caller must hold the r mutex
+	if engine, ok := r.execution.(OriginTrackingEngine); ok {
+		return engine.SimulateWithOrigins(request)
+	}
+	readRequest, writeMessages, err := r.execution.Simulate(request)
+	return readRequest, writeMessages, nil, err
+}
+
+func (r *sequencerInstance) sendWriteMessages(messages []MailboxMessage, origins []int) {
+	for i, msg := range messages {
 		// Check if belongs to cache
 		alreadySent := false
 		for _, cachedMsg := range r.writtenMessagesCache {
-			if cachedMsg.Equal(msg) {
+			if r.sameWrite(cachedMsg, msg) {
 				alreadySent = true
 				break
 			}
@@ -197,43 +517,112 @@ func (r *sequencerInstance) sendWriteMessages(messages []MailboxMessage) {
 		// Send if new message
 		r.network.SendMailboxMessage(msg.MailboxMessageHeader.DestChainID, msg)
 		r.writtenMessagesCache = append(r.writtenMessagesCache, msg)
+		origin := -1
+		if i < len(origins) {
+			origin = origins[i]
+		}
+		r.writtenOrigins = append(r.writtenOrigins, origin)
 	}
 }
 
-// consumeReceivedMailboxMessagesAndSimulate checks if any expected read mailbox messages have been received
-// If so, remove from the lists, and call run to simulate.
-func (r *sequencerInstance) consumeReceivedMailboxMessagesAndSimulate() error {
-	r.mu.Lock()
-	includedAny := false
+// expectReads returns the expected reads after the given read miss, and whether they must be fulfilled in order.
+// If the engine gives read hints, they replace the expected reads: the missed read followed by the hinted ones,
+// in order. Otherwise, the missed read is added to the already expected ones (if not there yet),
+// fulfilled in any order.
+// Returns ErrUnfulfillableRead if the missed or a hinted read is from a non-participant chain.
+func (r *sequencerInstance) expectReads(
+	expected []MailboxMessageHeader,
+	readRequest MailboxMessageHeader,
+) ([]MailboxMessageHeader, bool, error) {
+	// Caller must hold the r mutex
+	if err := r.checkParticipantRead(readRequest); err != nil {
+		return nil, false, err
+	}
+	var hints []MailboxMessageHeader
+	if engine, ok := r.execution.(ReadHintingEngine); ok {
+		hints = engine.ReadHints()
+	}
+	if len(hints) == 0 {
+		if slices.ContainsFunc(expected, readRequest.Equal) {
+			return expected, false, nil
+		}
+		return append(expected, readRequest), false, nil
+	}
+	reads := []MailboxMessageHeader{readRequest}
+	for _, hint := range hints {
+		if err := r.checkParticipantRead(hint); err != nil {
+			return nil, false, fmt.Errorf("read hint: %w", err)
+		}
+		if !slices.ContainsFunc(reads, hint.Equal) {
+			reads = append(reads, hint)
+		}
+	}
+	return reads, true, nil
+}
 
-	// Loop through expected messages
-	for idx := 0; idx < len(r.expectedReadRequests); {
-		expectedMsg := r.expectedReadRequests[idx]
-		matched := false
-		// Look if it exists in received messages
-		for receivedMsgIdx, receivedMsg := range r.pendingMessages {
-			if receivedMsg.MailboxMessageHeader.Equal(expectedMsg) {
-				// If found, add to mailboxOps
-				r.putInboxMessages = append(r.putInboxMessages, receivedMsg)
-				// Remove from lists
-				r.expectedReadRequests = append(
-					r.expectedReadRequests[:idx],
-					r.expectedReadRequests[idx+1:]...)
-				r.pendingMessages = append(
-					r.pendingMessages[:receivedMsgIdx],
-					r.pendingMessages[receivedMsgIdx+1:]...)
-				// Set flags
-				includedAny = true
-				matched = true
+// checkParticipantRead returns ErrUnfulfillableRead if the read is from a chain that doesn't participate
+// in the instance, since it could never be fulfilled.
+func (r *sequencerInstance) checkParticipantRead(read MailboxMessageHeader) error {
+	if !slices.Contains(r.participants, read.SourceChainID) {
+		return fmt.Errorf("source chain %d: %w", read.SourceChainID, ErrUnfulfillableRead)
+	}
+	return nil
+}
+
+// fulfillExpectedReads moves the pending messages matching expected reads to the put inbox messages,
+// returning whether any was moved. If ordered, reads are fulfilled in order up to the first one not received yet.
+func fulfillExpectedReads(
+	expected *[]MailboxMessageHeader,
+	pending *[]MailboxMessage,
+	putInbox *[]MailboxMessage,
+	ordered bool,
+) bool {
+	includedAny := false
+	for idx := 0; idx < len(*expected); {
+		matchIdx := slices.IndexFunc(*pending, func(msg MailboxMessage) bool {
+			return msg.MailboxMessageHeader.Equal((*expected)[idx])
+		})
+		if matchIdx < 0 {
+			if ordered {
 				break
 			}
-		}
-		// If matched, do not increment idx since we removed current message from the list
-		if !matched {
 			idx++
+			continue
+		}
+		// Do not increment idx since the current read is removed from the list
+		*putInbox = append(*putInbox, (*pending)[matchIdx])
+		*expected = slices.Delete(*expected, idx, idx+1)
+		*pending = slices.Delete(*pending, matchIdx, matchIdx+1)
+		includedAny = true
+	}
+	return includedAny
+}
+
+// sameWrite returns whether two write messages are the same according to the write dedup strategy.
+func (r *sequencerInstance) sameWrite(a, b MailboxMessage) bool {
+	if r.writeDedup == DedupByHeader {
+		return a.MailboxMessageHeader.Equal(b.MailboxMessageHeader)
+	}
+	return a.Equal(b)
+}
+
+// hasPendingMessage returns whether a received message already matches the given header.
+func (r *sequencerInstance) hasPendingMessage(header MailboxMessageHeader) bool {
+	// Caller must hold the r mutex
+	for _, msg := range r.pendingMessages {
+		if msg.MailboxMessageHeader.Equal(header) {
+			return true
 		}
 	}
+	return false
+}
 
+// consumeReceivedMailboxMessagesAndSimulate checks if any expected read mailbox messages have been received
+// If so, remove from the lists, and call run to simulate.
+func (r *sequencerInstance) consumeReceivedMailboxMessagesAndSimulate() error {
+	r.mu.Lock()
+	includedAny := fulfillExpectedReads(
+		&r.expectedReadRequests, &r.pendingMessages, &r.putInboxMessages, r.orderedReads)
 	r.mu.Unlock()
 	if includedAny {
 		r.logger.Info().Msg("Consuming mailbox messages and re-simulating.")
@@ -243,6 +632,7 @@ func (r *sequencerInstance) consumeReceivedMailboxMessagesAndSimulate() error {
 }
 
 // ProcessMailboxMessage processes an incoming mailbox message.
+// Messages with empty data are rejected with ErrEmptyMailboxData, unless allowed by WithAllowEmptyMailboxData.
 func (r *sequencerInstance) ProcessMailboxMessage(msg MailboxMessage) error {
 	r.mu.Lock()
 	if r.state != SeqStateSimulating {
@@ -252,23 +642,89 @@ func (r *sequencerInstance) ProcessMailboxMessage(msg MailboxMessage) error {
 			Msg("Ignoring mailbox message because not in simulating state")
 
 		r.mu.Unlock()
+		if r.onDroppedMailbox != nil {
+			r.onDroppedMailbox(msg)
+		}
 		return nil
 	}
 
+	if len(msg.Data) == 0 && !r.emptyDataAllowed(msg.Label) {
+		r.logger.Warn().
+			Uint64("source_chain_id", uint64(msg.MailboxMessageHeader.SourceChainID)).
+			Str("label", msg.MailboxMessageHeader.Label).
+			Msg("Rejecting mailbox message with empty data")
+
+		r.mu.Unlock()
+		return fmt.Errorf("label %q from chain %d: %w", msg.Label, msg.SourceChainID, ErrEmptyMailboxData)
+	}
+
 	r.logger.Info().
 		Uint64("source_chain_id", uint64(msg.MailboxMessageHeader.SourceChainID)).
 		Str("label", msg.MailboxMessageHeader.Label).
 		Msg("Adding mailbox message to pending list")
 
 	r.pendingMessages = append(r.pendingMessages, msg)
+	r.evictPendingMessages()
 	r.mu.Unlock()
 	return r.consumeReceivedMailboxMessagesAndSimulate()
 }
 
+// evictPendingMessages evicts the oldest pending messages not matching an expected read
+// until the buffer fits the WithMaxPendingMessages cap.
+func (r *sequencerInstance) evictPendingMessages() {
+	// Caller must hold the r mutex
+	if r.maxPendingMessages <= 0 {
+		return
+	}
+	for idx := 0; idx < len(r.pendingMessages) && len(r.pendingMessages) > r.maxPendingMessages; {
+		msg := r.pendingMessages[idx]
+		if r.isExpectedRead(msg.MailboxMessageHeader) {
+			idx++
+			continue
+		}
+		r.logger.Warn().
+			Uint64("source_chain_id", uint64(msg.MailboxMessageHeader.SourceChainID)).
+			Str("label", msg.MailboxMessageHeader.Label).
+			Int("max_pending_messages", r.maxPendingMessages).
+			Msg("Pending mailbox messages over the cap, evicting oldest unexpected message")
+		r.pendingMessages = append(r.pendingMessages[:idx], r.pendingMessages[idx+1:]...)
+	}
+}
+
+// isExpectedRead returns whether the header matches a read the instance is waiting for.
+func (r *sequencerInstance) isExpectedRead(header MailboxMessageHeader) bool {
+	// Caller must hold the r mutex
+	for _, expected := range r.expectedReadRequests {
+		if expected.Equal(header) {
+			return true
+		}
+	}
+	return false
+}
+
+// emptyDataAllowed returns whether a received mailbox message with the given label may have empty data.
+func (r *sequencerInstance) emptyDataAllowed(label string) bool {
+	// Caller must hold the r mutex
+	if r.allowEmptyData {
+		return true
+	}
+	_, ok := r.emptyDataLabels[label]
+	return ok
+}
+
 // ProcessDecidedMessage receives a decided message from the SP.
 func (r *sequencerInstance) ProcessDecidedMessage(decided bool) error {
 	r.mu.Lock()
 
+	contradictory := r.isContradictoryDecision(decided)
+	if contradictory {
+		r.logger.Warn().
+			Bool("local_vote", *r.localVote).
+			Bool("received_decided", decided).
+			Msg("Decided message contradicts local vote")
+	}
+	r.receivedDecided = true
+
 	if r.state == SeqStateDone {
 		r.logger.Info().
 			Bool("received_decided", decided).
@@ -276,6 +732,9 @@ func (r *sequencerInstance) ProcessDecidedMessage(decided bool) error {
 			Msg("Ignoring decided message because already done")
 
 		r.mu.Unlock()
+		if contradictory {
+			r.notifyContradictoryDecision(!decided, decided)
+		}
 		return nil
 	}
 
@@ -290,6 +749,9 @@ func (r *sequencerInstance) ProcessDecidedMessage(decided bool) error {
 		r.decisionState = compose.DecisionStateRejected
 	}
 	r.mu.Unlock()
+	if contradictory {
+		r.notifyContradictoryDecision(!decided, decided)
+	}
 	return nil
 }
 
@@ -325,5 +787,29 @@ func (r *sequencerInstance) Timeout() {
 
 	r.state = SeqStateDone
 	r.decisionState = compose.DecisionStateRejected
-	r.network.SendVote(false)
+	r.sendVote(false)
+}
+
+// sendVote sends the vote to the publisher and records it.
+func (r *sequencerInstance) sendVote(vote bool) {
+	// Caller must hold the r mutex
+	r.network.SendVote(vote)
+	r.localVote = &vote
+	if r.timeoutTimer != nil {
+		r.timeoutTimer.Stop()
+	}
+}
+
+// isContradictoryDecision returns whether the first received decided message contradicts the local vote.
+func (r *sequencerInstance) isContradictoryDecision(decided bool) bool {
+	// Caller must hold the r mutex
+	return !r.receivedDecided && r.localVote != nil && *r.localVote != decided
+}
+
+// notifyContradictoryDecision fires the contradictory decision hook, if set.
+// Must be called without holding the r mutex.
+func (r *sequencerInstance) notifyContradictoryDecision(localVote, decided bool) {
+	if r.onContradictoryDecision != nil {
+		r.onContradictoryDecision(localVote, decided)
+	}
 }
@@ -1,7 +1,9 @@
 package scp
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/compose-network/specs/compose"
 
@@ -81,6 +83,7 @@ func TestSequencer_ReadThenMailboxThenSuccess(t *testing.T) {
 		XTRequest: compose.XTRequest{
 			Transactions: []compose.TransactionRequest{
 				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
 			},
 		},
 	}
@@ -115,6 +118,8 @@ func TestSequencer_MultipleReadsOutOfOrder(t *testing.T) {
 		XTRequest: compose.XTRequest{
 			Transactions: []compose.TransactionRequest{
 				{ChainID: 1, Transactions: [][]byte{[]byte("x")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+				{ChainID: 3, Transactions: [][]byte{[]byte("c")}},
 			},
 		},
 	}
@@ -144,6 +149,7 @@ func TestSequencer_TimeoutBeforeVoteSendsFalse(t *testing.T) {
 		XTRequest: compose.XTRequest{
 			Transactions: []compose.TransactionRequest{
 				{ChainID: 1, Transactions: [][]byte{[]byte("x")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
 			},
 		},
 	}
@@ -187,6 +193,8 @@ func TestSequencer_TimeoutWithMultipleUnfulfilledReads(t *testing.T) {
 		XTRequest: compose.XTRequest{
 			Transactions: []compose.TransactionRequest{
 				{ChainID: 1, Transactions: [][]byte{[]byte("tx")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+				{ChainID: 3, Transactions: [][]byte{[]byte("c")}},
 			},
 		},
 	}
@@ -330,3 +338,730 @@ func TestSequencer_NoTransactions_ReturnsErrNoTransactions(t *testing.T) {
 	assert.Nil(t, seq)
 	assert.Empty(t, net.votes)
 }
+
+func TestSequencer_ReadMissRequestsMailbox(t *testing.T) {
+	a := makeMsg(compose.ChainID(2), "A", []byte("a"))
+	b := makeMsg(compose.ChainID(3), "B", []byte("b"))
+	// Simulation: need A, then need B, then success.
+	eng := &fakeExecutionEngine{
+		id: 1,
+		steps: []simulateResp{
+			{read: &a.MailboxMessageHeader},
+			{read: &b.MailboxMessageHeader},
+			{read: nil},
+		},
+	}
+	net := &fakeSequencerNetwork{}
+	requester := &fakeMailboxRequester{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("x")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+				{ChainID: 3, Transactions: [][]byte{[]byte("c")}},
+			},
+		},
+	}
+
+	seq, err := NewSequencerInstance(
+		inst, eng, net, compose.StateRoot{}, testLogger(), WithMailboxRequester(requester),
+	)
+	require.NoError(t, err)
+	require.NoError(t, seq.Run())
+
+	// The missed read is proactively requested
+	require.Len(t, requester.requests, 1)
+	assert.Equal(t, a.MailboxMessageHeader, requester.requests[0])
+
+	// B is buffered before being needed, so it's not requested
+	require.NoError(t, seq.ProcessMailboxMessage(b))
+	require.NoError(t, seq.ProcessMailboxMessage(a))
+	assert.Len(t, requester.requests, 1)
+
+	if assert.Len(t, net.votes, 1) {
+		assert.True(t, net.votes[0])
+	}
+}
+
+func TestSequencer_ReadMissRequestsMailbox_synchronous_delivery(t *testing.T) {
+	a := makeMsg(compose.ChainID(2), "A", []byte("a"))
+	eng := &fakeExecutionEngine{
+		id:    1,
+		steps: []simulateResp{{read: &a.MailboxMessageHeader}, {read: nil}},
+	}
+	net := &fakeSequencerNetwork{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("x")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("y")}},
+			},
+		},
+	}
+	requester := &fakeMailboxRequester{}
+	seq, err := NewSequencerInstance(
+		inst, eng, net, compose.StateRoot{}, testLogger(), WithMailboxRequester(requester),
+	)
+	require.NoError(t, err)
+
+	// The requester answers from within the request, which must not deadlock
+	requester.onRequest = func(MailboxMessageHeader) {
+		require.NoError(t, seq.ProcessMailboxMessage(a))
+	}
+	require.NoError(t, seq.Run())
+	assert.Len(t, requester.requests, 1)
+	assert.Equal(t, []bool{true}, net.votes)
+}
+
+func TestSequencer_SentWrites(t *testing.T) {
+	w1 := makeMsg(compose.ChainID(1), "W1", []byte("w1"))
+	w2 := makeMsg(compose.ChainID(1), "W2", []byte("w2"))
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	eng := &fakeExecutionEngine{
+		id: 1,
+		steps: []simulateResp{
+			{read: &need.MailboxMessageHeader, write: []MailboxMessage{w1}},
+			{write: []MailboxMessage{w1, w2}},
+		},
+	}
+	net := &fakeSequencerNetwork{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+
+	seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger())
+	require.NoError(t, err)
+	assert.False(t, seq.HasSentWrites())
+	assert.Equal(t, 0, seq.SentWriteCount())
+
+	require.NoError(t, seq.Run())
+	assert.True(t, seq.HasSentWrites())
+	assert.Equal(t, 1, seq.SentWriteCount())
+
+	// Re-simulation re-emits w1, which is only counted once
+	require.NoError(t, seq.ProcessMailboxMessage(need))
+	assert.Equal(t, 2, seq.SentWriteCount())
+	assert.Len(t, net.mailboxSent, 2)
+}
+
+func TestSequencer_ReadFromNonParticipantRejectsImmediately(t *testing.T) {
+	need := makeMsg(compose.ChainID(9), "X", []byte("d1"))
+	eng := &fakeExecutionEngine{
+		id:    1,
+		steps: []simulateResp{{read: &need.MailboxMessageHeader}},
+	}
+	net := &fakeSequencerNetwork{}
+	requester := &fakeMailboxRequester{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+
+	seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger(), WithMailboxRequester(requester))
+	require.NoError(t, err)
+
+	err = seq.Run()
+	require.ErrorIs(t, err, ErrUnfulfillableRead)
+	if assert.Len(t, net.votes, 1) {
+		assert.False(t, net.votes[0])
+	}
+	assert.Equal(t, compose.DecisionStateRejected, seq.DecisionState())
+	assert.Equal(t, SeqStateDone, requireSequencerImpl(t, seq).state)
+	assert.Empty(t, requireSequencerImpl(t, seq).expectedReadRequests)
+	assert.Empty(t, requester.requests)
+}
+
+func TestSequencer_OnContradictoryDecision(t *testing.T) {
+	type contradiction struct{ localVote, decided bool }
+	newSequencer := func(t *testing.T, steps []simulateResp) (SequencerInstance, *[]contradiction) {
+		t.Helper()
+		fired := &[]contradiction{}
+		inst := compose.Instance{
+			XTRequest: compose.XTRequest{
+				Transactions: []compose.TransactionRequest{
+					{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+					{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+				},
+			},
+		}
+		seq, err := NewSequencerInstance(
+			inst,
+			&fakeExecutionEngine{id: 1, steps: steps},
+			&fakeSequencerNetwork{},
+			compose.StateRoot{},
+			testLogger(),
+			WithOnContradictoryDecision(func(localVote, decided bool) {
+				*fired = append(*fired, contradiction{localVote: localVote, decided: decided})
+			}),
+		)
+		require.NoError(t, err)
+		return seq, fired
+	}
+
+	t.Run("voted_true_decided_false", func(t *testing.T) {
+		seq, fired := newSequencer(t, nil)
+		require.NoError(t, seq.Run())
+		require.NoError(t, seq.ProcessDecidedMessage(false))
+		assert.Equal(t, []contradiction{{localVote: true, decided: false}}, *fired)
+		assert.Equal(t, compose.DecisionStateRejected, seq.DecisionState())
+
+		// Later decided messages are ignored
+		require.NoError(t, seq.ProcessDecidedMessage(true))
+		assert.Len(t, *fired, 1)
+	})
+
+	t.Run("voted_false_decided_true", func(t *testing.T) {
+		need := makeMsg(compose.ChainID(2), "X", nil)
+		seq, fired := newSequencer(t, []simulateResp{{read: &need.MailboxMessageHeader}})
+		require.NoError(t, seq.Run())
+		seq.Timeout()
+		require.NoError(t, seq.ProcessDecidedMessage(true))
+		assert.Equal(t, []contradiction{{localVote: false, decided: true}}, *fired)
+		assert.Equal(t, compose.DecisionStateRejected, seq.DecisionState())
+	})
+
+	t.Run("matching_decision", func(t *testing.T) {
+		seq, fired := newSequencer(t, nil)
+		require.NoError(t, seq.Run())
+		require.NoError(t, seq.ProcessDecidedMessage(true))
+		assert.Empty(t, *fired)
+	})
+
+	t.Run("decided_before_voting", func(t *testing.T) {
+		seq, fired := newSequencer(t, nil)
+		require.NoError(t, seq.ProcessDecidedMessage(false))
+		assert.Empty(t, *fired)
+	})
+}
+
+func TestSequencer_WaitingLabels(t *testing.T) {
+	a := makeMsg(compose.ChainID(2), "B-label", []byte("a"))
+	b := makeMsg(compose.ChainID(3), "A-label", []byte("b"))
+	// Same label from another chain is only listed once
+	c := makeMsg(compose.ChainID(3), "B-label", []byte("c"))
+	eng := &fakeExecutionEngine{
+		id: 1,
+		steps: []simulateResp{
+			{read: &a.MailboxMessageHeader},
+			{read: &b.MailboxMessageHeader},
+			{read: &c.MailboxMessageHeader},
+			{read: nil},
+		},
+	}
+	net := &fakeSequencerNetwork{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("x")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("y")}},
+				{ChainID: 3, Transactions: [][]byte{[]byte("z")}},
+			},
+		},
+	}
+
+	seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger())
+	require.NoError(t, err)
+	assert.Empty(t, seq.WaitingLabels())
+
+	// Each run hits a new read miss while the previous ones are still outstanding
+	require.NoError(t, seq.Run())
+	assert.Equal(t, []string{"B-label"}, seq.WaitingLabels())
+	require.NoError(t, seq.Run())
+	require.NoError(t, seq.Run())
+	assert.Equal(t, []string{"A-label", "B-label"}, seq.WaitingLabels())
+
+	// Fulfilling one of the "B-label" reads keeps the label listed
+	require.NoError(t, seq.ProcessMailboxMessage(a))
+	assert.Equal(t, []string{"A-label", "B-label"}, seq.WaitingLabels())
+}
+
+func TestErrors_MatchSharedCategories(t *testing.T) {
+	assert.ErrorIs(t, ErrNoTransactions, compose.ErrNoTransactions)
+	assert.ErrorIs(t, ErrDuplicatedVote, compose.ErrDuplicatedVote)
+	assert.ErrorIs(t, ErrSenderNotParticipant, compose.ErrNotParticipant)
+	assert.ErrorIs(t, ErrUnfulfillableRead, compose.ErrNotParticipant)
+
+	eng := &fakeExecutionEngine{id: 1}
+	_, err := NewSequencerInstance(compose.Instance{}, eng, &fakeSequencerNetwork{}, compose.StateRoot{}, testLogger())
+	assert.ErrorIs(t, err, compose.ErrNoTransactions)
+}
+
+func TestSequencer_WrittenMessages(t *testing.T) {
+	w1 := makeMsg(compose.ChainID(1), "W1", []byte("w1"))
+	w2 := makeMsg(compose.ChainID(1), "W2", []byte("w2"))
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	eng := &fakeExecutionEngine{
+		id: 1,
+		steps: []simulateResp{
+			{read: &need.MailboxMessageHeader, write: []MailboxMessage{w1, w2}},
+			{write: []MailboxMessage{w2, w1}},
+		},
+	}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+
+	seq, err := NewSequencerInstance(inst, eng, &fakeSequencerNetwork{}, compose.StateRoot{}, testLogger())
+	require.NoError(t, err)
+	assert.Empty(t, seq.WrittenMessages())
+
+	require.NoError(t, seq.Run())
+	require.NoError(t, seq.ProcessMailboxMessage(need))
+
+	// Both writes appear exactly once across re-simulations
+	written := seq.WrittenMessages()
+	require.Len(t, written, 2)
+	assert.True(t, w1.Equal(written[0]))
+	assert.True(t, w2.Equal(written[1]))
+
+	// The returned messages don't alias the instance state
+	written[0].Data[0] = 'z'
+	assert.True(t, w1.Equal(seq.WrittenMessages()[0]))
+}
+
+func TestSequencer_WriteDedup(t *testing.T) {
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	write := makeMsg(compose.ChainID(1), "W", []byte("w1"))
+	retried := makeMsg(compose.ChainID(1), "W", []byte("w1-retried"))
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+	// The re-simulation writes the same header with different data
+	run := func(t *testing.T, opts ...SequencerOption) *fakeSequencerNetwork {
+		eng := &fakeExecutionEngine{
+			id: 1,
+			steps: []simulateResp{
+				{read: &need.MailboxMessageHeader, write: []MailboxMessage{write}},
+				{write: []MailboxMessage{retried}},
+			},
+		}
+		net := &fakeSequencerNetwork{}
+		seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger(), opts...)
+		require.NoError(t, err)
+		require.NoError(t, seq.Run())
+		require.NoError(t, seq.ProcessMailboxMessage(need))
+		require.Equal(t, []bool{true}, net.votes)
+		return net
+	}
+
+	t.Run("by_message", func(t *testing.T) {
+		net := run(t)
+		require.Len(t, net.mailboxSent, 2)
+		assert.True(t, write.Equal(net.mailboxSent[0].msg))
+		assert.True(t, retried.Equal(net.mailboxSent[1].msg))
+	})
+
+	t.Run("by_header", func(t *testing.T) {
+		net := run(t, WithWriteDedup(DedupByHeader))
+		require.Len(t, net.mailboxSent, 1)
+		assert.True(t, write.Equal(net.mailboxSent[0].msg))
+	})
+}
+
+func TestSequencer_WriteOrigins(t *testing.T) {
+	w1 := makeMsg(compose.ChainID(1), "W1", []byte("w1"))
+	w2 := makeMsg(compose.ChainID(1), "W2", []byte("w2"))
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	steps := []simulateResp{
+		{read: &need.MailboxMessageHeader, write: []MailboxMessage{w1}},
+		{write: []MailboxMessage{w1, w2}},
+	}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a"), []byte("b")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("c")}},
+			},
+		},
+	}
+
+	t.Run("reported by the engine", func(t *testing.T) {
+		eng := &fakeOriginTrackingEngine{
+			fakeExecutionEngine: fakeExecutionEngine{id: 1, steps: steps},
+			origins:             [][]int{{0}, {0, 1}},
+		}
+		seq, err := NewSequencerInstance(inst, eng, &fakeSequencerNetwork{}, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+		assert.Empty(t, seq.WriteOrigins())
+
+		require.NoError(t, seq.Run())
+		require.NoError(t, seq.ProcessMailboxMessage(need))
+
+		assert.Equal(t, map[string]int{
+			w1.MailboxMessageHeader.Key(): 0,
+			w2.MailboxMessageHeader.Key(): 1,
+		}, seq.WriteOrigins())
+	})
+
+	t.Run("unknown without engine support", func(t *testing.T) {
+		eng := &fakeExecutionEngine{id: 1, steps: steps}
+		seq, err := NewSequencerInstance(inst, eng, &fakeSequencerNetwork{}, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+
+		require.NoError(t, seq.Run())
+		require.NoError(t, seq.ProcessMailboxMessage(need))
+
+		assert.Equal(t, map[string]int{
+			w1.MailboxMessageHeader.Key(): -1,
+			w2.MailboxMessageHeader.Key(): -1,
+		}, seq.WriteOrigins())
+	})
+}
+
+func TestSequencer_EmptyMailboxData(t *testing.T) {
+	newSequencer := func(
+		t *testing.T,
+		need MailboxMessage,
+		opts ...SequencerOption,
+	) (SequencerInstance, *fakeSequencerNetwork) {
+		t.Helper()
+		eng := &fakeExecutionEngine{
+			id:    1,
+			steps: []simulateResp{{read: &need.MailboxMessageHeader}, {read: nil}},
+		}
+		net := &fakeSequencerNetwork{}
+		inst := compose.Instance{
+			XTRequest: compose.XTRequest{
+				Transactions: []compose.TransactionRequest{
+					{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+					{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+				},
+			},
+		}
+		seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger(), opts...)
+		require.NoError(t, err)
+		require.NoError(t, seq.Run())
+		return seq, net
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		need := makeMsg(compose.ChainID(2), "X", nil)
+		seq, net := newSequencer(t, need)
+
+		require.ErrorIs(t, seq.ProcessMailboxMessage(need), ErrEmptyMailboxData)
+		assert.Empty(t, net.votes)
+		assert.Equal(t, []string{"X"}, seq.WaitingLabels())
+	})
+
+	t.Run("lenient_for_label", func(t *testing.T) {
+		need := makeMsg(compose.ChainID(2), "X", nil)
+		seq, net := newSequencer(t, need, WithAllowEmptyMailboxData("X"))
+
+		require.NoError(t, seq.ProcessMailboxMessage(need))
+		assert.Equal(t, []bool{true}, net.votes)
+
+		// Other labels are still strict
+		other := makeMsg(compose.ChainID(2), "Y", nil)
+		seq, _ = newSequencer(t, other, WithAllowEmptyMailboxData("X"))
+		require.ErrorIs(t, seq.ProcessMailboxMessage(other), ErrEmptyMailboxData)
+	})
+
+	t.Run("lenient_for_all", func(t *testing.T) {
+		need := makeMsg(compose.ChainID(2), "Y", nil)
+		seq, net := newSequencer(t, need, WithAllowEmptyMailboxData())
+
+		require.NoError(t, seq.ProcessMailboxMessage(need))
+		assert.Equal(t, []bool{true}, net.votes)
+	})
+}
+
+func TestSequencer_OnDroppedMailbox(t *testing.T) {
+	eng := &fakeExecutionEngine{id: 1, steps: []simulateResp{ /* default success */ }}
+	net := &fakeSequencerNetwork{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+	var dropped []MailboxMessage
+	var seq SequencerInstance
+	hook := func(msg MailboxMessage) {
+		// Runs outside the lock, so the instance can be queried
+		assert.Equal(t, SeqStateWaitingDecided, seq.State())
+		dropped = append(dropped, msg)
+	}
+
+	seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger(), WithOnDroppedMailbox(hook))
+	require.NoError(t, err)
+	require.NoError(t, seq.Run())
+	require.Equal(t, []bool{true}, net.votes)
+
+	late := makeMsg(compose.ChainID(2), "late", []byte("d"))
+	require.NoError(t, seq.ProcessMailboxMessage(late))
+	require.Len(t, dropped, 1)
+	assert.True(t, late.Equal(dropped[0]))
+}
+
+func TestSequencer_MaxPendingMessages(t *testing.T) {
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	eng := &fakeExecutionEngine{
+		id:    1,
+		steps: []simulateResp{{read: &need.MailboxMessageHeader}},
+	}
+	net := &fakeSequencerNetwork{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+	seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger(), WithMaxPendingMessages(3))
+	require.NoError(t, err)
+	require.NoError(t, seq.Run())
+
+	// Flood with messages never matching the expected read
+	for i := range 10 {
+		flood := makeMsg(compose.ChainID(2), fmt.Sprintf("flood-%d", i), []byte("f"))
+		require.NoError(t, seq.ProcessMailboxMessage(flood))
+		assert.LessOrEqual(t, len(seq.SnapshotState().PendingMessages), 3)
+	}
+
+	// The oldest ones were evicted first
+	pending := seq.SnapshotState().PendingMessages
+	require.Len(t, pending, 3)
+	assert.Equal(t, "flood-7", pending[0].Label)
+	assert.Equal(t, "flood-9", pending[2].Label)
+
+	// A message matching the expected read is never evicted, even with the buffer full
+	require.NoError(t, seq.ProcessMailboxMessage(need))
+	assert.Equal(t, []bool{true}, net.votes)
+	assert.Len(t, seq.SnapshotState().PendingMessages, 2)
+}
+
+func TestSequencer_AutoTimeout(t *testing.T) {
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+	newSequencer := func(t *testing.T, clock *fakeClock) (SequencerInstance, *fakeSequencerNetwork) {
+		eng := &fakeExecutionEngine{
+			id:    1,
+			steps: []simulateResp{{read: &need.MailboxMessageHeader}},
+		}
+		net := &fakeSequencerNetwork{}
+		seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger(),
+			WithAutoTimeout(5*time.Second), WithClock(clock))
+		require.NoError(t, err)
+		require.NoError(t, seq.Run())
+		return seq, net
+	}
+
+	t.Run("fires", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(100, 0)}
+		seq, net := newSequencer(t, clock)
+		deadline, ok := seq.Deadline()
+		require.True(t, ok)
+		assert.Equal(t, time.Unix(105, 0), deadline)
+
+		clock.Advance(4 * time.Second)
+		assert.Empty(t, net.votes)
+		assert.Equal(t, SeqStateSimulating, seq.State())
+
+		clock.Advance(time.Second)
+		assert.Equal(t, []bool{false}, net.votes)
+		assert.Equal(t, SeqStateDone, seq.State())
+		assert.Equal(t, compose.DecisionStateRejected, seq.DecisionState())
+	})
+
+	t.Run("stopped_by_vote", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(100, 0)}
+		seq, net := newSequencer(t, clock)
+
+		require.NoError(t, seq.ProcessMailboxMessage(need))
+		require.Equal(t, []bool{true}, net.votes)
+
+		clock.Advance(10 * time.Second)
+		assert.Equal(t, []bool{true}, net.votes)
+		assert.Equal(t, SeqStateWaitingDecided, seq.State())
+	})
+}
+
+func TestSequencer_DryRun(t *testing.T) {
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	out := makeMsg(compose.ChainID(1), "Y", []byte("w1"))
+	out.DestChainID = 2
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+	newSequencer := func(t *testing.T) (SequencerInstance, *fakeSequencerNetwork) {
+		eng := &fakeExecutionEngine{
+			id: 1,
+			steps: []simulateResp{
+				{read: &need.MailboxMessageHeader, write: []MailboxMessage{out}},
+				{write: []MailboxMessage{out}},
+			},
+		}
+		net := &fakeSequencerNetwork{}
+		seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, seq.ProcessMailboxMessage(need))
+		return seq, net
+	}
+
+	dry, dryNet := newSequencer(t)
+	result, err := dry.DryRun()
+	require.NoError(t, err)
+
+	live, liveNet := newSequencer(t)
+	require.NoError(t, live.Run())
+
+	// The dry run predicts the real run
+	require.NotNil(t, result.Vote)
+	assert.Equal(t, liveNet.votes, []bool{*result.Vote})
+	assert.Equal(t, live.WrittenMessages(), result.WriteMessages)
+	assert.Empty(t, result.PendingReads)
+
+	// Without any side effect nor state change
+	assert.Empty(t, dryNet.votes)
+	assert.Empty(t, dryNet.mailboxSent)
+	assert.Empty(t, dry.WrittenMessages())
+	assert.Equal(t, SeqStateSimulating, dry.State())
+
+	t.Run("waiting_for_reads", func(t *testing.T) {
+		eng := &fakeExecutionEngine{id: 1, steps: []simulateResp{{read: &need.MailboxMessageHeader}}}
+		net := &fakeSequencerNetwork{}
+		seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+
+		result, err := seq.DryRun()
+		require.NoError(t, err)
+		assert.Nil(t, result.Vote)
+		assert.Equal(t, []MailboxMessageHeader{need.MailboxMessageHeader}, result.PendingReads)
+		assert.Empty(t, net.votes)
+	})
+
+	t.Run("not_simulating", func(t *testing.T) {
+		_, err := live.DryRun()
+		require.ErrorIs(t, err, ErrNotInSimulatingState)
+	})
+}
+
+func TestSequencer_ReadHints(t *testing.T) {
+	a := makeMsg(compose.ChainID(2), "A", []byte("a"))
+	b := makeMsg(compose.ChainID(3), "B", []byte("b"))
+	c := makeMsg(compose.ChainID(2), "C", []byte("c"))
+	needs := []MailboxMessageHeader{a.MailboxMessageHeader, b.MailboxMessageHeader, c.MailboxMessageHeader}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("x")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("y")}},
+				{ChainID: 3, Transactions: [][]byte{[]byte("z")}},
+			},
+		},
+	}
+	// Deliver the reads in reverse order of need
+	run := func(t *testing.T, engine ExecutionEngine) *fakeSequencerNetwork {
+		net := &fakeSequencerNetwork{}
+		seq, err := NewSequencerInstance(inst, engine, net, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, seq.Run())
+		for _, msg := range []MailboxMessage{c, b, a} {
+			require.NoError(t, seq.ProcessMailboxMessage(msg))
+		}
+		return net
+	}
+
+	t.Run("without_hints", func(t *testing.T) {
+		engine := &fakeOrderedReadsEngine{id: 1, needs: needs}
+		net := run(t, engine)
+		assert.Equal(t, []bool{true}, net.votes)
+		assert.Equal(t, 4, engine.calls)
+	})
+
+	t.Run("with_hints", func(t *testing.T) {
+		engine := &fakeReadHintingEngine{fakeOrderedReadsEngine: fakeOrderedReadsEngine{id: 1, needs: needs}}
+		net := run(t, engine)
+		assert.Equal(t, []bool{true}, net.votes)
+		// All reads are included at once when the first needed one arrives
+		assert.Equal(t, 2, engine.calls)
+	})
+
+	t.Run("empty_hints_fall_back_to_unordered", func(t *testing.T) {
+		engine := &fakeReadHintingEngine{
+			fakeOrderedReadsEngine: fakeOrderedReadsEngine{id: 1, needs: needs},
+			hints:                  func() []MailboxMessageHeader { return nil },
+		}
+		net := &fakeSequencerNetwork{}
+		seq, err := NewSequencerInstance(inst, engine, net, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, seq.Run())
+		assert.Equal(t, []MailboxMessageHeader{a.MailboxMessageHeader}, seq.PendingReads())
+
+		for _, msg := range []MailboxMessage{c, b, a} {
+			require.NoError(t, seq.ProcessMailboxMessage(msg))
+		}
+		assert.Equal(t, []bool{true}, net.votes)
+		assert.Equal(t, 4, engine.calls)
+	})
+
+	t.Run("non_participant_hint_rejects", func(t *testing.T) {
+		outsider := makeMsg(compose.ChainID(9), "D", []byte("d")).MailboxMessageHeader
+		engine := &fakeReadHintingEngine{
+			fakeOrderedReadsEngine: fakeOrderedReadsEngine{id: 1, needs: needs},
+			hints:                  func() []MailboxMessageHeader { return []MailboxMessageHeader{outsider} },
+		}
+		net := &fakeSequencerNetwork{}
+		seq, err := NewSequencerInstance(inst, engine, net, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+
+		result, err := seq.DryRun()
+		require.ErrorIs(t, err, ErrUnfulfillableRead)
+		require.NotNil(t, result.Vote)
+		assert.False(t, *result.Vote)
+
+		require.ErrorIs(t, seq.Run(), ErrUnfulfillableRead)
+		assert.Equal(t, []bool{false}, net.votes)
+		assert.Equal(t, compose.DecisionStateRejected, seq.DecisionState())
+	})
+
+	t.Run("out_of_order_reads_wait", func(t *testing.T) {
+		engine := &fakeReadHintingEngine{fakeOrderedReadsEngine: fakeOrderedReadsEngine{id: 1, needs: needs}}
+		seq, err := NewSequencerInstance(inst, engine, &fakeSequencerNetwork{}, compose.StateRoot{}, testLogger())
+		require.NoError(t, err)
+		require.NoError(t, seq.Run())
+		assert.Equal(t, needs, seq.PendingReads())
+
+		require.NoError(t, seq.ProcessMailboxMessage(b))
+		assert.Equal(t, 1, engine.calls, "B is not simulated before A")
+		assert.Equal(t, needs, seq.PendingReads())
+
+		require.NoError(t, seq.ProcessMailboxMessage(a))
+		assert.Equal(t, 2, engine.calls)
+		assert.Equal(t, []MailboxMessageHeader{c.MailboxMessageHeader}, seq.PendingReads())
+	})
+}
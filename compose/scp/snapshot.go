@@ -0,0 +1,126 @@
+package scp
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/compose-network/specs/compose"
+)
+
+var ErrSnapshotChainMismatch = errors.New("snapshot chain ID does not match the execution engine")
+
+// SequencerSnapshot is a serializable copy of the full internal state of a sequencer instance,
+// used to persist in-flight instances and restore them after a restart.
+type SequencerSnapshot struct {
+	ChainID              compose.ChainID
+	State                SequencerState
+	DecisionState        compose.DecisionState
+	LocalVote            *bool
+	ReceivedDecided      bool
+	Txs                  [][]byte
+	Participants         []compose.ChainID
+	ExpectedReadRequests []MailboxMessageHeader
+	// Whether ExpectedReadRequests come from read hints, and so must be fulfilled in order
+	OrderedReads     bool
+	PendingMessages  []MailboxMessage
+	PutInboxMessages []MailboxMessage
+	VMSnapshot       compose.StateRoot
+	WrittenMessages  []MailboxMessage
+	// Index of the transaction that produced each written message (-1 if unknown)
+	WrittenOrigins []int
+}
+
+// SnapshotState returns a deep copy of the instance state.
+func (r *sequencerInstance) SnapshotState() SequencerSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return SequencerSnapshot{
+		ChainID:              r.execution.ChainID(),
+		State:                r.state,
+		DecisionState:        r.decisionState,
+		LocalVote:            cloneVote(r.localVote),
+		ReceivedDecided:      r.receivedDecided,
+		Txs:                  compose.CloneByteSlices(r.txs),
+		Participants:         slices.Clone(r.participants),
+		ExpectedReadRequests: append([]MailboxMessageHeader(nil), r.expectedReadRequests...),
+		OrderedReads:         r.orderedReads,
+		PendingMessages:      cloneMailboxMessages(r.pendingMessages),
+		PutInboxMessages:     cloneMailboxMessages(r.putInboxMessages),
+		VMSnapshot:           r.vmSnapshot,
+		WrittenMessages:      cloneMailboxMessages(r.writtenMessagesCache),
+		WrittenOrigins:       slices.Clone(r.writtenOrigins),
+	}
+}
+
+// RestoreSequencerInstance rebuilds a sequencer instance in the exact state captured by SnapshotState.
+// The transactions are taken from the snapshot, so the original request isn't needed.
+func RestoreSequencerInstance(
+	snapshot SequencerSnapshot,
+	execution ExecutionEngine,
+	network SequencerNetwork,
+	logger zerolog.Logger,
+	opts ...SequencerOption,
+) (SequencerInstance, error) {
+	if snapshot.ChainID != execution.ChainID() {
+		return nil, ErrSnapshotChainMismatch
+	}
+	if len(snapshot.Txs) == 0 {
+		return nil, ErrNoTransactions
+	}
+
+	r := &sequencerInstance{
+		mu:                   sync.Mutex{},
+		execution:            execution,
+		network:              network,
+		state:                snapshot.State,
+		decisionState:        snapshot.DecisionState,
+		localVote:            cloneVote(snapshot.LocalVote),
+		receivedDecided:      snapshot.ReceivedDecided,
+		txs:                  compose.CloneByteSlices(snapshot.Txs),
+		participants:         slices.Clone(snapshot.Participants),
+		putInboxMessages:     cloneMailboxMessages(snapshot.PutInboxMessages),
+		expectedReadRequests: append(make([]MailboxMessageHeader, 0), snapshot.ExpectedReadRequests...),
+		orderedReads:         snapshot.OrderedReads,
+		pendingMessages:      cloneMailboxMessages(snapshot.PendingMessages),
+		vmSnapshot:           snapshot.VMSnapshot,
+		writtenMessagesCache: cloneMailboxMessages(snapshot.WrittenMessages),
+		writtenOrigins:       make([]int, len(snapshot.WrittenMessages)),
+		clock:                systemClock{},
+		logger:               logger,
+	}
+	for i := range r.writtenOrigins {
+		r.writtenOrigins[i] = -1
+		if i < len(snapshot.WrittenOrigins) {
+			r.writtenOrigins[i] = snapshot.WrittenOrigins[i]
+		}
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.startAutoTimeout()
+
+	return r, nil
+}
+
+func cloneMailboxMessages(messages []MailboxMessage) []MailboxMessage {
+	cloned := make([]MailboxMessage, len(messages))
+	for i, msg := range messages {
+		cloned[i] = MailboxMessage{
+			MailboxMessageHeader: msg.MailboxMessageHeader,
+			Data:                 append([]byte(nil), msg.Data...),
+		}
+	}
+	return cloned
+}
+
+func cloneVote(vote *bool) *bool {
+	if vote == nil {
+		return nil
+	}
+	v := *vote
+	return &v
+}
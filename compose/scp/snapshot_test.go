@@ -0,0 +1,114 @@
+package scp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/compose-network/specs/compose"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequencer_SnapshotRestoreMidRead(t *testing.T) {
+	need := makeMsg(compose.ChainID(2), "X", []byte("d1"))
+	write := makeMsg(compose.ChainID(1), "W", []byte("w1"))
+	write.DestChainID = compose.ChainID(2)
+	eng := &fakeExecutionEngine{
+		id:    1,
+		steps: []simulateResp{{read: &need.MailboxMessageHeader, write: []MailboxMessage{write}}},
+	}
+	net := &fakeSequencerNetwork{}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a"), []byte("b")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("c")}},
+			},
+		},
+	}
+
+	seq, err := NewSequencerInstance(inst, eng, net, compose.StateRoot{7}, testLogger())
+	require.NoError(t, err)
+	require.NoError(t, seq.Run())
+	require.Len(t, net.mailboxSent, 1)
+	require.Empty(t, net.votes)
+
+	// Persist through JSON, as an operator would to disk
+	encoded, err := json.Marshal(seq.SnapshotState())
+	require.NoError(t, err)
+	var snapshot SequencerSnapshot
+	require.NoError(t, json.Unmarshal(encoded, &snapshot))
+	assert.Equal(t, SeqStateSimulating, snapshot.State)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, snapshot.Txs)
+	assert.Equal(t, []MailboxMessageHeader{need.MailboxMessageHeader}, snapshot.ExpectedReadRequests)
+
+	// Restored instance re-emits the same write, which must not be sent again
+	restoredEng := &fakeExecutionEngine{
+		id:    1,
+		steps: []simulateResp{{write: []MailboxMessage{write}}},
+	}
+	restoredNet := &fakeSequencerNetwork{}
+	restored, err := RestoreSequencerInstance(snapshot, restoredEng, restoredNet, testLogger())
+	require.NoError(t, err)
+	assert.Equal(t, compose.DecisionStatePending, restored.DecisionState())
+
+	require.NoError(t, restored.ProcessMailboxMessage(need))
+	if assert.Len(t, restoredNet.votes, 1) {
+		assert.True(t, restoredNet.votes[0])
+	}
+	assert.Empty(t, restoredNet.mailboxSent)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, restoredEng.lastReq.Transactions)
+	assert.Equal(t, compose.StateRoot{7}, restoredEng.lastReq.Snapshot)
+	require.Len(t, restoredEng.lastReq.PutInboxMessages, 1)
+	assert.True(t, need.Equal(restoredEng.lastReq.PutInboxMessages[0]))
+}
+
+func TestSequencer_SnapshotRestoreOrderedReads(t *testing.T) {
+	a := makeMsg(compose.ChainID(2), "A", []byte("a"))
+	b := makeMsg(compose.ChainID(2), "B", []byte("b"))
+	needs := []MailboxMessageHeader{a.MailboxMessageHeader, b.MailboxMessageHeader}
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("x")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("y")}},
+			},
+		},
+	}
+	engine := &fakeReadHintingEngine{fakeOrderedReadsEngine: fakeOrderedReadsEngine{id: 1, needs: needs}}
+	seq, err := NewSequencerInstance(inst, engine, &fakeSequencerNetwork{}, compose.StateRoot{}, testLogger())
+	require.NoError(t, err)
+	require.NoError(t, seq.Run())
+
+	encoded, err := json.Marshal(seq.SnapshotState())
+	require.NoError(t, err)
+	var snapshot SequencerSnapshot
+	require.NoError(t, json.Unmarshal(encoded, &snapshot))
+	assert.True(t, snapshot.OrderedReads)
+
+	restoredEngine := &fakeReadHintingEngine{fakeOrderedReadsEngine: fakeOrderedReadsEngine{id: 1, needs: needs}}
+	restoredNet := &fakeSequencerNetwork{}
+	restored, err := RestoreSequencerInstance(snapshot, restoredEngine, restoredNet, testLogger())
+	require.NoError(t, err)
+
+	// B still waits for A after the restore
+	require.NoError(t, restored.ProcessMailboxMessage(b))
+	assert.Equal(t, 0, restoredEngine.calls)
+	assert.Equal(t, needs, restored.PendingReads())
+
+	require.NoError(t, restored.ProcessMailboxMessage(a))
+	assert.Equal(t, 1, restoredEngine.calls)
+	assert.Equal(t, []bool{true}, restoredNet.votes)
+}
+
+func TestSequencer_RestoreRejectsInvalidSnapshots(t *testing.T) {
+	snapshot := SequencerSnapshot{ChainID: 1, Txs: [][]byte{[]byte("a")}}
+
+	_, err := RestoreSequencerInstance(snapshot, &fakeExecutionEngine{id: 2}, &fakeSequencerNetwork{}, testLogger())
+	require.ErrorIs(t, err, ErrSnapshotChainMismatch)
+
+	snapshot.Txs = nil
+	_, err = RestoreSequencerInstance(snapshot, &fakeExecutionEngine{id: 1}, &fakeSequencerNetwork{}, testLogger())
+	require.ErrorIs(t, err, ErrNoTransactions)
+}
@@ -0,0 +1,31 @@
+package scp
+
+import "time"
+
+// StuckReport describes an instance still simulating after its deadline.
+type StuckReport struct {
+	Instance SequencerInstance
+	// Time elapsed since the deadline
+	Overdue time.Duration
+	// Mailbox reads the instance is still waiting for
+	PendingReads []MailboxMessageHeader
+}
+
+// FindStuck returns a report for each instance that is still simulating past its deadline (see WithDeadline),
+// in the same order as the given instances. Instances without a deadline are never reported.
+// Nodes running many instances can use it to log or drain the stuck ones (e.g. by calling Timeout).
+func FindStuck(instances []SequencerInstance, now time.Time) []StuckReport {
+	reports := make([]StuckReport, 0)
+	for _, instance := range instances {
+		deadline, ok := instance.Deadline()
+		if !ok || !now.After(deadline) || instance.State() != SeqStateSimulating {
+			continue
+		}
+		reports = append(reports, StuckReport{
+			Instance:     instance,
+			Overdue:      now.Sub(deadline),
+			PendingReads: instance.PendingReads(),
+		})
+	}
+	return reports
+}
@@ -0,0 +1,54 @@
+package scp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-network/specs/compose"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStuck(t *testing.T) {
+	now := time.Unix(1_000, 0)
+	need := makeMsg(compose.ChainID(2), "NEED", nil)
+	inst := compose.Instance{
+		XTRequest: compose.XTRequest{
+			Transactions: []compose.TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+	newSequencer := func(steps []simulateResp, opts ...SequencerOption) SequencerInstance {
+		eng := &fakeExecutionEngine{id: 1, steps: steps}
+		seq, err := NewSequencerInstance(inst, eng, &fakeSequencerNetwork{}, compose.StateRoot{}, testLogger(), opts...)
+		require.NoError(t, err)
+		return seq
+	}
+	readMiss := []simulateResp{{read: &need.MailboxMessageHeader}}
+
+	// Voted, waiting for the decided message
+	voted := newSequencer(nil, WithDeadline(now.Add(-time.Second)))
+	require.NoError(t, voted.Run())
+	// Done by timeout
+	done := newSequencer(readMiss, WithDeadline(now.Add(-time.Second)))
+	require.NoError(t, done.Run())
+	done.Timeout()
+	// Waiting for a read past the deadline
+	stuck := newSequencer(readMiss, WithDeadline(now.Add(-3*time.Second)))
+	require.NoError(t, stuck.Run())
+	// Waiting for a read, but before the deadline
+	waiting := newSequencer(readMiss, WithDeadline(now.Add(time.Second)))
+	require.NoError(t, waiting.Run())
+	// Waiting for a read without a deadline
+	noDeadline := newSequencer(readMiss)
+	require.NoError(t, noDeadline.Run())
+
+	reports := FindStuck([]SequencerInstance{voted, done, stuck, waiting, noDeadline}, now)
+	require.Len(t, reports, 1)
+	assert.Same(t, stuck, reports[0].Instance)
+	assert.Equal(t, 3*time.Second, reports[0].Overdue)
+	assert.Equal(t, []MailboxMessageHeader{need.MailboxMessageHeader}, reports[0].PendingReads)
+}
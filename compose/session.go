@@ -0,0 +1,61 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrSessionInUse = errors.New("session ID already allocated")
+
+// SessionAllocator issues session IDs, detecting collisions with those reserved explicitly
+// (e.g. agreed with other nodes). It's safe for concurrent use.
+type SessionAllocator struct {
+	mu sync.Mutex
+	// Next candidate ID for Next
+	next SessionID
+	// Allocated IDs, issued by Next or reserved
+	allocated map[SessionID]struct{}
+}
+
+// NewSessionAllocator creates an allocator whose first issued ID is start,
+// so that nodes sharing the same start (seed) issue the same IDs.
+func NewSessionAllocator(start SessionID) *SessionAllocator {
+	return &SessionAllocator{
+		next:      start,
+		allocated: make(map[SessionID]struct{}),
+	}
+}
+
+// Next issues a new session ID, greater than any previously issued one, skipping reserved IDs.
+func (a *SessionAllocator) Next() SessionID {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		id := a.next
+		a.next++
+		if _, ok := a.allocated[id]; !ok {
+			a.allocated[id] = struct{}{}
+			return id
+		}
+	}
+}
+
+// Reserve allocates the given session ID, returning ErrSessionInUse if it was already allocated.
+func (a *SessionAllocator) Reserve(id SessionID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.allocated[id]; ok {
+		return fmt.Errorf("session %d: %w", id, ErrSessionInUse)
+	}
+	a.allocated[id] = struct{}{}
+	return nil
+}
+
+// Release frees an allocated session ID (e.g. once its instance is done), so that it can be reserved again.
+// Next never issues it again.
+func (a *SessionAllocator) Release(id SessionID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.allocated, id)
+}
@@ -0,0 +1,64 @@
+package compose
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAllocator_Next(t *testing.T) {
+	allocator := NewSessionAllocator(SessionID(10))
+	assert.Equal(t, SessionID(10), allocator.Next())
+	assert.Equal(t, SessionID(11), allocator.Next())
+
+	// Reserved IDs are skipped
+	require.NoError(t, allocator.Reserve(SessionID(12)))
+	assert.Equal(t, SessionID(13), allocator.Next())
+
+	// Released IDs are never issued again
+	allocator.Release(SessionID(13))
+	assert.Equal(t, SessionID(14), allocator.Next())
+}
+
+func TestSessionAllocator_Reserve(t *testing.T) {
+	allocator := NewSessionAllocator(SessionID(1))
+	issued := allocator.Next()
+
+	require.ErrorIs(t, allocator.Reserve(issued), ErrSessionInUse)
+	require.NoError(t, allocator.Reserve(SessionID(5)))
+	require.ErrorIs(t, allocator.Reserve(SessionID(5)), ErrSessionInUse)
+
+	allocator.Release(SessionID(5))
+	require.NoError(t, allocator.Reserve(SessionID(5)))
+}
+
+func TestSessionAllocator_ConcurrentNext(t *testing.T) {
+	allocator := NewSessionAllocator(SessionID(0))
+	const workers, perWorker = 8, 100
+
+	var mu sync.Mutex
+	seen := make(map[SessionID]struct{})
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := SessionID(0)
+			for i := range perWorker {
+				id := allocator.Next()
+				if i > 0 {
+					assert.Greater(t, id, last)
+				}
+				last = id
+
+				mu.Lock()
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, seen, workers*perWorker)
+}
@@ -0,0 +1,15 @@
+package compose
+
+import "time"
+
+// SuggestedTimeout returns a timeout for an instance of the given request,
+// computed as base + perTx * (total number of transactions in the request).
+// Larger requests need more simulation time, so the orchestration layer can use it
+// when scheduling the protocol Timeout calls.
+func SuggestedTimeout(req XTRequest, base, perTx time.Duration) time.Duration {
+	txCount := 0
+	for _, txReq := range req.Transactions {
+		txCount += len(txReq.Transactions)
+	}
+	return base + time.Duration(txCount)*perTx
+}
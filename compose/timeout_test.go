@@ -0,0 +1,22 @@
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestedTimeout_EmptyRequest(t *testing.T) {
+	assert.Equal(t, 2*time.Second, SuggestedTimeout(XTRequest{}, 2*time.Second, 100*time.Millisecond))
+}
+
+func TestSuggestedTimeout_ScalesWithTransactions(t *testing.T) {
+	req := XTRequest{
+		Transactions: []TransactionRequest{
+			{ChainID: 1, Transactions: make([][]byte, 40)},
+			{ChainID: 2, Transactions: make([][]byte, 60)},
+		},
+	}
+	assert.Equal(t, 12*time.Second, SuggestedTimeout(req, 2*time.Second, 100*time.Millisecond))
+}
@@ -0,0 +1,139 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTranscriptNotStarted   = errors.New("event before the instance was started")
+	ErrTranscriptRestarted    = errors.New("instance started twice")
+	ErrVoteAfterDecided       = errors.New("vote after the instance was decided")
+	ErrDecidedTwice           = errors.New("instance decided twice")
+	ErrInconsistentDecision   = errors.New("decision is inconsistent with the votes")
+	ErrUnknownTranscriptEvent = errors.New("unknown transcript event")
+
+	ErrTranscriptDuplicatedVote = NewError(ErrDuplicatedVote, "chain voted twice")
+	ErrTranscriptNotParticipant = NewError(ErrNotParticipant, "vote from a chain that does not participate")
+)
+
+// TranscriptEventType is the kind of protocol message recorded in an instance transcript.
+type TranscriptEventType int
+
+const (
+	TranscriptStartInstance TranscriptEventType = iota
+	TranscriptVote
+	TranscriptDecided
+)
+
+func (t TranscriptEventType) String() string {
+	switch t {
+	case TranscriptStartInstance:
+		return "StartInstance"
+	case TranscriptVote:
+		return "Vote"
+	case TranscriptDecided:
+		return "Decided"
+	default:
+		return "Unknown"
+	}
+}
+
+// TranscriptEvent is a protocol message of an instance, in the order it was sent.
+type TranscriptEvent struct {
+	Type TranscriptEventType
+	// Voting chain (only for votes)
+	ChainID ChainID
+	// Vote or decision value (unused for StartInstance)
+	Value bool
+}
+
+// TranscriptValidator checks, event by event, that the messages of an instance follow the protocol:
+// the instance is started once before anything else, each participant votes at most once and before
+// the decision, and the decision is consistent with the votes under the unanimous rule
+// (accepted only if all participants voted true, rejected otherwise, e.g. on a false vote or a timeout).
+type TranscriptValidator struct {
+	participants map[ChainID]struct{}
+	started      bool
+	votes        map[ChainID]bool
+	decided      *bool
+}
+
+// NewTranscriptValidator creates a validator for the transcript of the given instance.
+func NewTranscriptValidator(instance Instance) *TranscriptValidator {
+	participants := make(map[ChainID]struct{})
+	for _, chainID := range instance.Chains() {
+		participants[chainID] = struct{}{}
+	}
+	return &TranscriptValidator{
+		participants: participants,
+		votes:        make(map[ChainID]bool),
+	}
+}
+
+// ValidateTranscript checks that the ordered events of the instance form a valid (possibly still
+// undecided) transcript, returning the error of the first invalid event.
+func ValidateTranscript(instance Instance, events []TranscriptEvent) error {
+	validator := NewTranscriptValidator(instance)
+	for i, event := range events {
+		if err := validator.Apply(event); err != nil {
+			return fmt.Errorf("event %d (%s): %w", i, event.Type, err)
+		}
+	}
+	return nil
+}
+
+// Apply checks the next event of the transcript and, if valid, records it.
+func (v *TranscriptValidator) Apply(event TranscriptEvent) error {
+	switch event.Type {
+	case TranscriptStartInstance:
+		if v.started {
+			return ErrTranscriptRestarted
+		}
+		v.started = true
+		return nil
+	case TranscriptVote:
+		if !v.started {
+			return ErrTranscriptNotStarted
+		}
+		if v.decided != nil {
+			return ErrVoteAfterDecided
+		}
+		if _, ok := v.participants[event.ChainID]; !ok {
+			return ErrTranscriptNotParticipant
+		}
+		if _, ok := v.votes[event.ChainID]; ok {
+			return ErrTranscriptDuplicatedVote
+		}
+		v.votes[event.ChainID] = event.Value
+		return nil
+	case TranscriptDecided:
+		if !v.started {
+			return ErrTranscriptNotStarted
+		}
+		if v.decided != nil {
+			return ErrDecidedTwice
+		}
+		if event.Value != v.allVotedTrue() {
+			return ErrInconsistentDecision
+		}
+		decided := event.Value
+		v.decided = &decided
+		return nil
+	default:
+		return ErrUnknownTranscriptEvent
+	}
+}
+
+// allVotedTrue returns whether every participant voted true. Instances without participants are never accepted.
+func (v *TranscriptValidator) allVotedTrue() bool {
+	if len(v.participants) == 0 || len(v.votes) < len(v.participants) {
+		return false
+	}
+	for _, vote := range v.votes {
+		if !vote {
+			return false
+		}
+	}
+	return true
+}
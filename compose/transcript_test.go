@@ -0,0 +1,96 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func transcriptInstance() Instance {
+	return Instance{
+		ID: InstanceID{1},
+		XTRequest: XTRequest{
+			Transactions: []TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+				{ChainID: 2, Transactions: [][]byte{[]byte("b")}},
+			},
+		},
+	}
+}
+
+func start() TranscriptEvent {
+	return TranscriptEvent{Type: TranscriptStartInstance}
+}
+
+func vote(chainID ChainID, value bool) TranscriptEvent {
+	return TranscriptEvent{Type: TranscriptVote, ChainID: chainID, Value: value}
+}
+
+func decided(value bool) TranscriptEvent {
+	return TranscriptEvent{Type: TranscriptDecided, Value: value}
+}
+
+func TestValidateTranscript_valid(t *testing.T) {
+	instance := transcriptInstance()
+
+	// Accepted
+	require.NoError(t, ValidateTranscript(instance, []TranscriptEvent{
+		start(), vote(2, true), vote(1, true), decided(true),
+	}))
+	// Rejected by a false vote, before the other chain voted
+	require.NoError(t, ValidateTranscript(instance, []TranscriptEvent{
+		start(), vote(1, false), decided(false),
+	}))
+	// Rejected by timeout
+	require.NoError(t, ValidateTranscript(instance, []TranscriptEvent{
+		start(), vote(1, true), decided(false),
+	}))
+	// Still undecided
+	require.NoError(t, ValidateTranscript(instance, []TranscriptEvent{start(), vote(1, true)}))
+}
+
+func TestValidateTranscript_invalid(t *testing.T) {
+	instance := transcriptInstance()
+
+	tests := []struct {
+		name   string
+		events []TranscriptEvent
+		err    error
+	}{
+		{"vote_before_start", []TranscriptEvent{vote(1, true)}, ErrTranscriptNotStarted},
+		{"started_twice", []TranscriptEvent{start(), start()}, ErrTranscriptRestarted},
+		{"vote_after_decided", []TranscriptEvent{
+			start(), vote(1, false), decided(false), vote(2, true),
+		}, ErrVoteAfterDecided},
+		{"decided_twice", []TranscriptEvent{start(), decided(false), decided(false)}, ErrDecidedTwice},
+		{"accepted_with_false_vote", []TranscriptEvent{
+			start(), vote(1, true), vote(2, false), decided(true),
+		}, ErrInconsistentDecision},
+		{"accepted_without_all_votes", []TranscriptEvent{
+			start(), vote(1, true), decided(true),
+		}, ErrInconsistentDecision},
+		{"rejected_with_all_true_votes", []TranscriptEvent{
+			start(), vote(1, true), vote(2, true), decided(false),
+		}, ErrInconsistentDecision},
+		{"duplicated_vote", []TranscriptEvent{start(), vote(1, true), vote(1, true)}, ErrDuplicatedVote},
+		{"non_participant_vote", []TranscriptEvent{start(), vote(3, true)}, ErrNotParticipant},
+		{"unknown_event", []TranscriptEvent{start(), {Type: TranscriptEventType(9)}}, ErrUnknownTranscriptEvent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, ValidateTranscript(instance, tt.events), tt.err)
+		})
+	}
+}
+
+func TestTranscriptValidator_incremental(t *testing.T) {
+	validator := NewTranscriptValidator(transcriptInstance())
+	require.NoError(t, validator.Apply(start()))
+	require.NoError(t, validator.Apply(vote(1, true)))
+
+	// Invalid events are not recorded
+	require.ErrorIs(t, validator.Apply(decided(true)), ErrInconsistentDecision)
+	require.NoError(t, validator.Apply(vote(2, true)))
+	require.NoError(t, validator.Apply(decided(true)))
+}
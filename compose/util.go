@@ -14,3 +14,52 @@ func CloneByteSlices(src [][]byte) [][]byte {
 	}
 	return out
 }
+
+// Clone returns a deep copy of the request, so that the copy doesn't alias the original transaction bytes.
+// Nil slices stay nil.
+func (r XTRequest) Clone() XTRequest {
+	if r.Transactions == nil {
+		return XTRequest{}
+	}
+	out := XTRequest{
+		Transactions: make([]TransactionRequest, len(r.Transactions)),
+	}
+	for i, txReq := range r.Transactions {
+		out.Transactions[i] = TransactionRequest{
+			ChainID:      txReq.ChainID,
+			Transactions: CloneByteSlices(txReq.Transactions),
+		}
+	}
+	return out
+}
+
+// Clone returns a deep copy of the instance, including its request.
+func (i Instance) Clone() Instance {
+	return Instance{
+		ID:             i.ID,
+		PeriodID:       i.PeriodID,
+		SequenceNumber: i.SequenceNumber,
+		XTRequest:      i.XTRequest.Clone(),
+	}
+}
+
+// WorkEstimate is the simulation load of a request for a single chain.
+type WorkEstimate struct {
+	Transactions int
+	Bytes        int
+}
+
+// EstimateWork returns the simulation load (transaction count and bytes) of the request per chain,
+// e.g. to balance instances across nodes. Transaction requests for the same chain are added up.
+func EstimateWork(xtRequest XTRequest) map[ChainID]WorkEstimate {
+	estimates := make(map[ChainID]WorkEstimate)
+	for _, txReq := range xtRequest.Transactions {
+		estimate := estimates[txReq.ChainID]
+		estimate.Transactions += len(txReq.Transactions)
+		for _, tx := range txReq.Transactions {
+			estimate.Bytes += len(tx)
+		}
+		estimates[txReq.ChainID] = estimate
+	}
+	return estimates
+}
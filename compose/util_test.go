@@ -0,0 +1,70 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXTRequest_Clone(t *testing.T) {
+	req := XTRequest{
+		Transactions: []TransactionRequest{
+			{ChainID: 1, Transactions: [][]byte{[]byte("a"), nil}},
+			{ChainID: 2, Transactions: nil},
+		},
+	}
+
+	clone := req.Clone()
+	require.Equal(t, req, clone)
+	assert.Nil(t, clone.Transactions[0].Transactions[1])
+	assert.Nil(t, clone.Transactions[1].Transactions)
+
+	clone.Transactions[0].Transactions[0][0] = 'z'
+	clone.Transactions[0].ChainID = 9
+	assert.Equal(t, []byte("a"), req.Transactions[0].Transactions[0])
+	assert.Equal(t, ChainID(1), req.Transactions[0].ChainID)
+
+	assert.Nil(t, XTRequest{}.Clone().Transactions)
+}
+
+func TestInstance_Clone(t *testing.T) {
+	instance := Instance{
+		ID:             InstanceID{1, 2, 3},
+		PeriodID:       4,
+		SequenceNumber: 5,
+		XTRequest: XTRequest{
+			Transactions: []TransactionRequest{
+				{ChainID: 1, Transactions: [][]byte{[]byte("a")}},
+			},
+		},
+	}
+
+	clone := instance.Clone()
+	require.Equal(t, instance, clone)
+	// Non-addressable values can be cloned too
+	assert.Equal(t, instance, map[int]Instance{0: instance}[0].Clone())
+
+	clone.ID[0] = 9
+	clone.XTRequest.Transactions[0].Transactions[0][0] = 'z'
+	assert.Equal(t, InstanceID{1, 2, 3}, instance.ID)
+	assert.Equal(t, []byte("a"), instance.XTRequest.Transactions[0].Transactions[0])
+}
+
+func TestEstimateWork(t *testing.T) {
+	req := XTRequest{
+		Transactions: []TransactionRequest{
+			{ChainID: 1, Transactions: [][]byte{[]byte("abc"), []byte("de")}},
+			{ChainID: 2, Transactions: [][]byte{[]byte("f")}},
+			{ChainID: 1, Transactions: [][]byte{[]byte("ghij")}},
+			{ChainID: 3, Transactions: nil},
+		},
+	}
+
+	assert.Equal(t, map[ChainID]WorkEstimate{
+		1: {Transactions: 3, Bytes: 9},
+		2: {Transactions: 1, Bytes: 1},
+		3: {},
+	}, EstimateWork(req))
+	assert.Empty(t, EstimateWork(XTRequest{}))
+}